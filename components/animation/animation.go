@@ -25,16 +25,41 @@ package animation
 
 import (
 	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/geometry"
 )
 
+// PlayMode is how the frames of a Sequence are played
+type PlayMode int
+
+//goland:noinspection GoUnusedConst
+const (
+	Loop        = PlayMode(iota) // Loop plays the frames from first to last and starts again, the default
+	Once                         // Once plays the frames from first to last and stays in the last frame
+	PingPong                     // PingPong plays the frames from first to last and back to the first, again and again
+	Reverse                      // Reverse plays the frames from last to first and starts again
+	ReverseOnce                  // ReverseOnce plays the frames from last to first and stays in the first frame
+)
+
+// Frame is a frame of a Sequence
+type Frame struct {
+	Name     string         // Name is the sprite name in the Sequence Sheet
+	Duration float32        // Duration is number of seconds to show this frame, 0 for the Sequence Delay
+	Offset   geometry.Point // Offset moves the entity while this frame is show, before scale and flip
+	FlipX    bool           // FlipX indicates if this frame is flipped in the X-Assis
+	FlipY    bool           // FlipY indicates if this frame is flipped in the Y-Assis
+}
+
 // Sequence represent a set of frames that will be render with a delay
 type Sequence struct {
-	Sheet    string  // Sheet is the sprite sheet where the animation sprites are
-	Base     string  // Base is the base name for each frame. ex : Idle_%d.png
-	Rotation float32 // Rotation for this AnimationSequence
-	Scale    float32 // Scale for this AnimationSequence
-	Frames   int32   // Frames are the number of frame in this animation
-	Delay    float32 // Delay number of seconds to wait in each frame
+	Sheet     string   // Sheet is the sprite sheet where the animation sprites are
+	Base      string   // Base is the base name for each frame. ex : Idle_%d.png
+	Rotation  float32  // Rotation for this AnimationSequence
+	Scale     float32  // Scale for this AnimationSequence
+	Frames    int32    // Frames are the number of frame in this animation
+	Delay     float32  // Delay number of seconds to wait in each frame
+	Mode      PlayMode // Mode is the PlayMode for this AnimationSequence, Loop by default
+	Next      string   // Next is the sequence to play when Once or ReverseOnce finish, empty to stay in the end
+	FrameList []Frame  // FrameList is an explicit list of frames, if is set Base and Frames are not used
 }
 
 // Type return this goecs.ComponentType
@@ -44,10 +69,13 @@ func (s Sequence) Type() goecs.ComponentType {
 
 // State allow to easily switch animations
 type State struct {
-	Current string  // Current is the animation that is running
-	Speed   float32 // Speed is the current animation speed
-	Time    float32 // Time is the time in this frame
-	Frame   int32   // Frame is the current frame number
+	Current string         // Current is the animation that is running
+	Speed   float32        // Speed is the current animation speed
+	Time    float32        // Time is the time in this frame
+	Frame   int32          // Frame is the current frame number
+	Back    bool           // Back indicates that a PingPong sequence is going back to the first frame
+	Ended   bool           // Ended indicates that a Once or ReverseOnce sequence has finished
+	Offset  geometry.Point // Offset is the current frame offset applied to the entity
 }
 
 // Type return this goecs.ComponentType
@@ -69,6 +97,110 @@ func (a Animation) Type() goecs.ComponentType {
 	return TYPE.Animation
 }
 
+// ConditionOp is how a Condition checks a StateMachine parameter
+type ConditionOp string
+
+//goland:noinspection GoUnusedConst
+const (
+	Greater   = ConditionOp(">")       // Greater checks that a float parameter is greater than the Value
+	Less      = ConditionOp("<")       // Less checks that a float parameter is less than the Value
+	Equals    = ConditionOp("==")      // Equals checks that a float parameter is equal to the Value
+	NotEquals = ConditionOp("!=")      // NotEquals checks that a float parameter is not equal to the Value
+	IsTrue    = ConditionOp("true")    // IsTrue checks that a bool parameter is true
+	IsFalse   = ConditionOp("false")   // IsFalse checks that a bool parameter is false
+	Triggered = ConditionOp("trigger") // Triggered checks that a trigger parameter is set, using it
+)
+
+// AnyState is the From of a Transition that could happen from any state
+const AnyState = "*"
+
+// Condition is a check on a StateMachine parameter
+type Condition struct {
+	Param string      `json:"param"` // Param is the parameter name
+	Op    ConditionOp `json:"op"`    // Op is the ConditionOp
+	Value float32     `json:"value"` // Value is the value to compare float parameters with
+}
+
+// Transition is a change between two states of a StateMachine
+type Transition struct {
+	From       string      `json:"from"`       // From is the state that we change from, or AnyState
+	To         string      `json:"to"`         // To is the state that we change to
+	Conditions []Condition `json:"conditions"` // Conditions are checks that all need to be true to change
+	ExitTime   float32     `json:"exitTime"`   // ExitTime is the part of the state sequence, 1 for all, to wait
+}
+
+// StateMachine change the animation.Animation Current sequence with the Transitions between its States, base on its
+// float, bool and trigger parameters
+type StateMachine struct {
+	Initial     string             `json:"initial"`     // Initial is the state that we start with
+	States      map[string]string  `json:"states"`      // States are the sequence name for each state
+	Transitions []Transition       `json:"transitions"` // Transitions are checked in order, the first valid is used
+	Floats      map[string]float32 `json:"floats"`      // Floats are the float parameters
+	Bools       map[string]bool    `json:"bools"`       // Bools are the bool parameters
+	Triggers    map[string]bool    `json:"triggers"`    // Triggers are parameters that are cleared when used
+}
+
+// Type return this goecs.ComponentType
+func (sm StateMachine) Type() goecs.ComponentType {
+	return TYPE.StateMachine
+}
+
+// SetFloat returns this StateMachine with a float parameter set to a value
+func (sm StateMachine) SetFloat(name string, value float32) StateMachine {
+	if sm.Floats == nil {
+		sm.Floats = make(map[string]float32)
+	}
+	sm.Floats[name] = value
+	return sm
+}
+
+// SetBool returns this StateMachine with a bool parameter set to a value
+func (sm StateMachine) SetBool(name string, value bool) StateMachine {
+	if sm.Bools == nil {
+		sm.Bools = make(map[string]bool)
+	}
+	sm.Bools[name] = value
+	return sm
+}
+
+// SetTrigger returns this StateMachine with a trigger parameter set, it will be cleared when a transition use it
+func (sm StateMachine) SetTrigger(name string) StateMachine {
+	if sm.Triggers == nil {
+		sm.Triggers = make(map[string]bool)
+	}
+	sm.Triggers[name] = true
+	return sm
+}
+
+// Clone returns a copy of this StateMachine that does not share its parameters
+func (sm StateMachine) Clone() StateMachine {
+	clone := sm
+	clone.Floats = make(map[string]float32, len(sm.Floats))
+	for k, v := range sm.Floats {
+		clone.Floats[k] = v
+	}
+	clone.Bools = make(map[string]bool, len(sm.Bools))
+	for k, v := range sm.Bools {
+		clone.Bools[k] = v
+	}
+	clone.Triggers = make(map[string]bool, len(sm.Triggers))
+	for k, v := range sm.Triggers {
+		clone.Triggers[k] = v
+	}
+	return clone
+}
+
+// MachineState is the state of a StateMachine
+type MachineState struct {
+	Current string  // Current is the current state
+	Time    float32 // Time is how long we have been in the current state, with the animation speed
+}
+
+// Type return this goecs.ComponentType
+func (ms MachineState) Type() goecs.ComponentType {
+	return TYPE.MachineState
+}
+
 type types struct {
 	// Animation is the goecs.ComponentType for animation.Animation
 	Animation goecs.ComponentType
@@ -76,13 +208,19 @@ type types struct {
 	State goecs.ComponentType
 	// Sequence is the goecs.ComponentType for animation.Sequence
 	Sequence goecs.ComponentType
+	// StateMachine is the goecs.ComponentType for animation.StateMachine
+	StateMachine goecs.ComponentType
+	// MachineState is the goecs.ComponentType for animation.MachineState
+	MachineState goecs.ComponentType
 }
 
 // TYPE hold the goecs.ComponentType for our animation components
 var TYPE = types{
-	Animation: goecs.NewComponentType(),
-	State:     goecs.NewComponentType(),
-	Sequence:  goecs.NewComponentType(),
+	Animation:    goecs.NewComponentType(),
+	State:        goecs.NewComponentType(),
+	Sequence:     goecs.NewComponentType(),
+	StateMachine: goecs.NewComponentType(),
+	MachineState: goecs.NewComponentType(),
 }
 
 type gets struct {
@@ -92,6 +230,10 @@ type gets struct {
 	State func(e *goecs.Entity) State
 	// Layer gets a Layer from a goecs.Entity
 	Sequence func(e *goecs.Entity) Sequence
+	// StateMachine gets a StateMachine from a goecs.Entity
+	StateMachine func(e *goecs.Entity) StateMachine
+	// MachineState gets a MachineState from a goecs.Entity
+	MachineState func(e *goecs.Entity) MachineState
 }
 
 // Get animation component
@@ -108,4 +250,12 @@ var Get = gets{
 	Sequence: func(e *goecs.Entity) Sequence {
 		return e.Get(TYPE.Sequence).(Sequence)
 	},
+	// StateMachine gets a animation.StateMachine from a goecs.Entity
+	StateMachine: func(e *goecs.Entity) StateMachine {
+		return e.Get(TYPE.StateMachine).(StateMachine)
+	},
+	// MachineState gets a animation.MachineState from a goecs.Entity
+	MachineState: func(e *goecs.Entity) MachineState {
+		return e.Get(TYPE.MachineState).(MachineState)
+	},
 }
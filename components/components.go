@@ -24,6 +24,7 @@
 package components
 
 import (
+	"github.com/juan-medina/gosge/components/color"
 	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/lafriks/go-tiled"
 )
@@ -58,10 +59,120 @@ type SoundDef struct {
 
 // TiledMapDef defines a tiled map
 type TiledMapDef struct {
-	Data       *tiled.Map                   // Data is the internal map data
-	Cols       int32                        // Cols is the number of cols in the map
-	Rows       int32                        // Rows is the number of rows in the map
-	Size       geometry.Size                // Size is the map size in pixels
-	TileSize   geometry.Size                // TileSize is the size per each tile in pixels
-	Properties map[uint32]map[string]string // Properties are properties per each global block id
+	Data         *tiled.Map                      // Data is the internal map data
+	Cols         int32                           // Cols is the number of cols in the map
+	Rows         int32                           // Rows is the number of rows in the map
+	Size         geometry.Size                   // Size is the map size in pixels
+	TileSize     geometry.Size                   // TileSize is the size per each tile in pixels
+	Properties   map[uint32]map[string]string    // Properties are properties per each global block id
+	Sprites      map[uint32]SpriteDef            // Sprites are the sprite definitions per each global block id
+	StaggerAxis  string                          // StaggerAxis is the stagger axis, "x" or "y", on staggered/hexagonal maps
+	StaggerIndex string                          // StaggerIndex is which indexes, "odd" or "even", are shifted
+	Animations   map[uint32][]TileAnimationFrame // Animations are the animation frames per each animated global block id
+	Layers       []TiledLayerDef                 // Layers are the map layers in draw order, including the nested ones
+	Background   color.Solid                     // Background is the map background color, transparent if has none
+	Infinite     bool                            // Infinite indicates that the map is infinite, its tiles are in chunks
+	OriginCol    int                             // OriginCol is the col in the infinite map of the first map col
+	OriginRow    int                             // OriginRow is the row in the infinite map of the first map row
+	ChunkWidth   int                             // ChunkWidth is the width in cells of the chunks of an infinite map
+	ChunkHeight  int                             // ChunkHeight is the height in cells of the chunks of an infinite map
+}
+
+// TiledChunkKey identifies a chunk of an infinite tiled map layer by its first cell in the infinite map
+type TiledChunkKey struct {
+	Col int // Col is the first col of the chunk
+	Row int // Row is the first row of the chunk
+}
+
+// TiledChunks are the tiles per chunk of an infinite tiled map layer, only the chunks in the map are stored
+type TiledChunks map[TiledChunkKey][]*tiled.LayerTile
+
+// TiledLayerKind is the kind of a layer in a tiled map
+type TiledLayerKind int
+
+//goland:noinspection GoUnusedConst
+const (
+	TiledTileLayer   = TiledLayerKind(iota) // TiledTileLayer is a layer of tiles
+	TiledObjectLayer                        // TiledObjectLayer is a layer of objects
+	TiledImageLayer                         // TiledImageLayer is a layer with a single image
+)
+
+// TiledLayerDef defines a layer in a tiled map, layers inside groups have the values of their groups already applied
+type TiledLayerDef struct {
+	Kind     TiledLayerKind     // Kind is the kind of layer
+	Name     string             // Name is the layer name
+	Tiles    *tiled.Layer       // Tiles is the internal layer data for a TiledTileLayer
+	Chunks   TiledChunks        // Chunks are the tiles of a TiledTileLayer of an infinite map
+	Objects  *tiled.ObjectGroup // Objects is the internal layer data for a TiledObjectLayer
+	Image    *tiled.ImageLayer  // Image is the internal layer data for a TiledImageLayer
+	Visible  bool               // Visible indicates if the layer, and all its groups, are visible
+	Opacity  float32            // Opacity is the layer opacity, from 0 to 1
+	Tint     color.Solid        // Tint is the color.Solid that the layer is multiplied by
+	Offset   geometry.Point     // Offset is how much, in map pixels, the layer is moved
+	Parallax geometry.Point     // Parallax is how much the layer moves relative to the map movement, 1 for the same
+}
+
+// TileAnimationFrame is a frame of an animated tile in a tiled map
+type TileAnimationFrame struct {
+	GID      uint32  // GID is the global block id to show in this frame
+	Duration float32 // Duration is how long this frame is show in seconds
+}
+
+// LDtkProjectDef defines a LDtk project
+type LDtkProjectDef struct {
+	Levels map[string]LDtkLevelDef // Levels are the project levels by identifier
+}
+
+// LDtkLevelDef defines a level of a LDtk project
+type LDtkLevelDef struct {
+	Identifier    string                 // Identifier is the level identifier
+	WorldPosition geometry.Point         // WorldPosition is the level position in the project world, in pixels
+	Size          geometry.Size          // Size is the level size in pixels
+	Fields        map[string]interface{} // Fields are the level field values by identifier, as read from the JSON
+	Layers        []LDtkLayerDef         // Layers are the level layers in draw order
+}
+
+// LDtkLayerDef defines a layer of a LDtk level
+type LDtkLayerDef struct {
+	Identifier   string          // Identifier is the layer identifier
+	Type         string          // Type is the layer type: IntGrid, Entities, Tiles or AutoLayer
+	GridSize     float32         // GridSize is the size of a cell in pixels
+	Cols         int             // Cols is the number of cols in the layer
+	Rows         int             // Rows is the number of rows in the layer
+	Offset       geometry.Point  // Offset is the layer offset in pixels
+	Opacity      float32         // Opacity is the layer opacity, from 0 to 1
+	Visible      bool            // Visible indicates if the layer is visible
+	IntGrid      []int           // IntGrid are the values of an IntGrid layer per cell, row by row, 0 for empty
+	IntGridNames map[int]string  // IntGridNames are the identifiers of the IntGrid values
+	Tiles        []LDtkTileDef   // Tiles are the layer tiles, including the auto-layer ones, in draw order
+	Entities     []LDtkEntityDef // Entities are the entity instances in an Entities layer
+}
+
+// LDtkTileDef defines a tile in a LDtk layer
+type LDtkTileDef struct {
+	ID       int            // ID is the tile id in its tileset
+	Sprite   string         // Sprite is the sprite name in the project sprite sheet
+	Position geometry.Point // Position is the tile position in the layer in pixels
+	FlipX    bool           // FlipX indicates if the tile is flipped horizontally
+	FlipY    bool           // FlipY indicates if the tile is flipped vertically
+	Alpha    float32        // Alpha is the tile opacity, from 0 to 1
+}
+
+// LDtkEntityDef defines an entity instance in a LDtk layer
+type LDtkEntityDef struct {
+	IID        string                 // IID is the entity instance unique id
+	Identifier string                 // Identifier is the entity definition identifier
+	Position   geometry.Point         // Position is the entity pivot position in the layer in pixels
+	Size       geometry.Size          // Size is the entity size in pixels
+	Pivot      geometry.Point         // Pivot is the relative pivot 0..1 in each axis
+	Tags       []string               // Tags are the entity definition tags
+	Fields     map[string]interface{} // Fields are the entity field values by identifier, as read from the JSON
+	Sprite     string                 // Sprite is the sprite name in the project sprite sheet, if it has a tile
+}
+
+// LocaleDef defines a locale with its translations
+type LocaleDef struct {
+	Language string              // Language is the language of this locale, ex: en, es_ES
+	Plural   func(n int) int     // Plural returns the plural form to use for a given number
+	Texts    map[string][]string // Texts are the translations per key, with one entry per plural form
 }
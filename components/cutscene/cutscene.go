@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+// Package cutscene has the components for playing timelines of timed actions, like intros or in game scenes
+package cutscene
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+)
+
+// ActionKind is what an Action does
+type ActionKind string
+
+//goland:noinspection GoUnusedConst
+const (
+	Move      = ActionKind("move")      // Move moves the Actor to the Point in the Action Duration
+	Animate   = ActionKind("animate")   // Animate changes the Current animation, or skeleton clip, of the Actor to Name
+	Dialog    = ActionKind("dialog")    // Dialog shows the Text in the ui.Text of the Actor, hiding it after Duration
+	Sound     = ActionKind("sound")     // Sound plays the sound Name with a Volume
+	Music     = ActionKind("music")     // Music plays the music Name with a Volume
+	WaitInput = ActionKind("waitInput") // WaitInput stops the timeline until a key, mouse or gamepad button is down
+	Signal    = ActionKind("signal")    // Signal emits the Action Signal, or an events.CutsceneSignalEvent with Name
+)
+
+// Action is something that happens in a Timeline at a given Time
+type Action struct {
+	Kind     ActionKind     `json:"kind"`     // Kind is the ActionKind
+	Time     float32        `json:"time"`     // Time is when the action starts, in seconds from the timeline start
+	Duration float32        `json:"duration"` // Duration is how long a Move or Dialog last, in seconds
+	Actor    string         `json:"actor"`    // Actor is the name in the Actor of the entity to use
+	Point    geometry.Point `json:"point"`    // Point is the geometry.Point where a Move ends
+	Ease     effects.Easing `json:"ease"`     // Ease is the effects.Easing for a Move, in JSON by name, ex: "inOutQuad"
+	Name     string         `json:"name"`     // Name is the animation, sound, music or signal name
+	Text     string         `json:"text"`     // Text is the text for a Dialog
+	Volume   float32        `json:"volume"`   // Volume is the volume for a Sound or Music, 0 is the same as 1
+	Signal   interface{}    `json:"-"`        // Signal is the signal to emit for a Signal action, not for JSON
+}
+
+// Track is a list of actions, tracks run at the same time
+type Track struct {
+	Name    string   `json:"name"`    // Name is the track name
+	Actions []Action `json:"actions"` // Actions are the track actions
+}
+
+// Timeline is a set of Tracks of timed actions
+type Timeline struct {
+	Tracks []Track `json:"tracks"` // Tracks are the timeline tracks
+}
+
+// Length returns when the last action of the Timeline ends, in seconds
+func (t Timeline) Length() (length float32) {
+	for _, track := range t.Tracks {
+		for _, action := range track.Actions {
+			if end := action.Time + action.Duration; end > length {
+				length = end
+			}
+		}
+	}
+	return
+}
+
+// Actor gives a name to an entity so a Timeline could use it
+type Actor struct {
+	Name string // Name is the actor name
+}
+
+// Type return this goecs.ComponentType
+func (a Actor) Type() goecs.ComponentType {
+	return TYPE.Actor
+}
+
+// Player plays a Timeline, when it finish an events.CutsceneFinishedEvent will be trigger and the player will be
+// removed from the entity
+type Player struct {
+	Timeline Timeline // Timeline is the Timeline to play
+	Speed    float32  // Speed is a multiplier of the timeline speed, ex: 4 for fast-forward, 0 is the same as 1
+	Paused   bool     // Paused stops the timeline while is true
+	Skip     bool     // Skip ends every action at once, without playing sounds or waiting for input
+}
+
+// Type return this goecs.ComponentType
+func (p Player) Type() goecs.ComponentType {
+	return TYPE.Player
+}
+
+// ActionState is the state of an Action in a Player
+type ActionState struct {
+	Started bool           // Started indicates if the Action has started
+	Ended   bool           // Ended indicates if the Action has ended
+	From    geometry.Point // From is where the Actor was when a Move started
+}
+
+// State is the state of a Player
+type State struct {
+	Time    float32         // Time is the current timeline time
+	Waiting bool            // Waiting indicates that we wait for input
+	Actions [][]ActionState // Actions is the state of each action per each track
+}
+
+// Type return this goecs.ComponentType
+func (s State) Type() goecs.ComponentType {
+	return TYPE.State
+}
+
+type types struct {
+	// Actor is the goecs.ComponentType for cutscene.Actor
+	Actor goecs.ComponentType
+	// Player is the goecs.ComponentType for cutscene.Player
+	Player goecs.ComponentType
+	// State is the goecs.ComponentType for cutscene.State
+	State goecs.ComponentType
+}
+
+// TYPE hold the goecs.ComponentType for our cutscene components
+var TYPE = types{
+	Actor:  goecs.NewComponentType(),
+	Player: goecs.NewComponentType(),
+	State:  goecs.NewComponentType(),
+}
+
+type gets struct {
+	// Actor gets a cutscene.Actor from a goecs.Entity
+	Actor func(e *goecs.Entity) Actor
+	// Player gets a cutscene.Player from a goecs.Entity
+	Player func(e *goecs.Entity) Player
+	// State gets a cutscene.State from a goecs.Entity
+	State func(e *goecs.Entity) State
+}
+
+// Get a cutscene component
+var Get = gets{
+	// Actor gets a cutscene.Actor from a goecs.Entity
+	Actor: func(e *goecs.Entity) Actor {
+		return e.Get(TYPE.Actor).(Actor)
+	},
+	// Player gets a cutscene.Player from a goecs.Entity
+	Player: func(e *goecs.Entity) Player {
+		return e.Get(TYPE.Player).(Player)
+	},
+	// State gets a cutscene.State from a goecs.Entity
+	State: func(e *goecs.Entity) State {
+		return e.Get(TYPE.State).(State)
+	},
+}
@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package effects
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Easing is a curve that changes the rate of an effects.Tween over time
+type Easing int
+
+//goland:noinspection GoUnusedConst
+const (
+	EaseLinear       = Easing(iota) // EaseLinear changes at a constant rate, the default
+	EaseInQuad                      // EaseInQuad accelerates from zero velocity
+	EaseOutQuad                     // EaseOutQuad decelerates to zero velocity
+	EaseInOutQuad                   // EaseInOutQuad accelerates until halfway, then decelerates
+	EaseInCubic                     // EaseInCubic accelerates from zero velocity
+	EaseOutCubic                    // EaseOutCubic decelerates to zero velocity
+	EaseInOutCubic                  // EaseInOutCubic accelerates until halfway, then decelerates
+	EaseInQuart                     // EaseInQuart accelerates from zero velocity
+	EaseOutQuart                    // EaseOutQuart decelerates to zero velocity
+	EaseInOutQuart                  // EaseInOutQuart accelerates until halfway, then decelerates
+	EaseInQuint                     // EaseInQuint accelerates from zero velocity
+	EaseOutQuint                    // EaseOutQuint decelerates to zero velocity
+	EaseInOutQuint                  // EaseInOutQuint accelerates until halfway, then decelerates
+	EaseInSine                      // EaseInSine accelerates following a sine curve
+	EaseOutSine                     // EaseOutSine decelerates following a sine curve
+	EaseInOutSine                   // EaseInOutSine accelerates and decelerates following a sine curve
+	EaseInExpo                      // EaseInExpo accelerates exponentially
+	EaseOutExpo                     // EaseOutExpo decelerates exponentially
+	EaseInOutExpo                   // EaseInOutExpo accelerates and decelerates exponentially
+	EaseInCirc                      // EaseInCirc accelerates following a circular curve
+	EaseOutCirc                     // EaseOutCirc decelerates following a circular curve
+	EaseInOutCirc                   // EaseInOutCirc accelerates and decelerates following a circular curve
+	EaseInBack                      // EaseInBack goes slightly back before moving forward
+	EaseOutBack                     // EaseOutBack overshoots the end before settling
+	EaseInOutBack                   // EaseInOutBack goes back at the start and overshoots the end
+	EaseInElastic                   // EaseInElastic oscillates at the start like a spring
+	EaseOutElastic                  // EaseOutElastic oscillates at the end like a spring
+	EaseInOutElastic                // EaseInOutElastic oscillates at the start and the end like a spring
+	EaseInBounce                    // EaseInBounce bounces at the start
+	EaseOutBounce                   // EaseOutBounce bounces at the end like a dropped ball
+	EaseInOutBounce                 // EaseInOutBounce bounces at the start and the end
+)
+
+const (
+	easeBack         = 1.70158
+	easeBackInOut    = easeBack * 1.525
+	easeElastic      = (2 * math.Pi) / 3
+	easeElasticInOut = (2 * math.Pi) / 4.5
+)
+
+// easingNames are the names of each Easing, without the Ease prefix, for decoding them from JSON
+var easingNames = [...]string{
+	"linear", "inQuad", "outQuad", "inOutQuad", "inCubic", "outCubic", "inOutCubic", "inQuart", "outQuart",
+	"inOutQuart", "inQuint", "outQuint", "inOutQuint", "inSine", "outSine", "inOutSine", "inExpo", "outExpo",
+	"inOutExpo", "inCirc", "outCirc", "inOutCirc", "inBack", "outBack", "inOutBack", "inElastic", "outElastic",
+	"inOutElastic", "inBounce", "outBounce", "inOutBounce",
+}
+
+// UnmarshalJSON decodes an Easing from its name, with or without the Ease prefix and in any case, ex: "inOutQuad",
+// or from its number
+func (e *Easing) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var value int
+		if err = json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("invalid easing %s", data)
+		}
+		*e = Easing(value)
+		return nil
+	}
+	key := strings.TrimPrefix(strings.ToLower(name), "ease")
+	for i, n := range easingNames {
+		if strings.ToLower(n) == key {
+			*e = Easing(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown easing %q", name)
+}
+
+// Apply returns the eased progress for a linear progress t, from 0 to 1, back and elastic curves may return values
+// outside that range
+func (e Easing) Apply(t float32) float32 {
+	if t <= 0 {
+		return 0
+	}
+	if t >= 1 {
+		return 1
+	}
+	x := float64(t)
+	var r float64
+	switch e {
+	case EaseInQuad:
+		r = x * x
+	case EaseOutQuad:
+		r = 1 - (1-x)*(1-x)
+	case EaseInOutQuad:
+		r = inOut(x, 2)
+	case EaseInCubic:
+		r = x * x * x
+	case EaseOutCubic:
+		r = 1 - math.Pow(1-x, 3)
+	case EaseInOutCubic:
+		r = inOut(x, 3)
+	case EaseInQuart:
+		r = math.Pow(x, 4)
+	case EaseOutQuart:
+		r = 1 - math.Pow(1-x, 4)
+	case EaseInOutQuart:
+		r = inOut(x, 4)
+	case EaseInQuint:
+		r = math.Pow(x, 5)
+	case EaseOutQuint:
+		r = 1 - math.Pow(1-x, 5)
+	case EaseInOutQuint:
+		r = inOut(x, 5)
+	case EaseInSine:
+		r = 1 - math.Cos((x*math.Pi)/2)
+	case EaseOutSine:
+		r = math.Sin((x * math.Pi) / 2)
+	case EaseInOutSine:
+		r = -(math.Cos(math.Pi*x) - 1) / 2
+	case EaseInExpo:
+		r = math.Pow(2, 10*x-10)
+	case EaseOutExpo:
+		r = 1 - math.Pow(2, -10*x)
+	case EaseInOutExpo:
+		if x < 0.5 {
+			r = math.Pow(2, 20*x-10) / 2
+		} else {
+			r = (2 - math.Pow(2, -20*x+10)) / 2
+		}
+	case EaseInCirc:
+		r = 1 - math.Sqrt(1-x*x)
+	case EaseOutCirc:
+		r = math.Sqrt(1 - (x-1)*(x-1))
+	case EaseInOutCirc:
+		if x < 0.5 {
+			r = (1 - math.Sqrt(1-math.Pow(2*x, 2))) / 2
+		} else {
+			r = (math.Sqrt(1-math.Pow(-2*x+2, 2)) + 1) / 2
+		}
+	case EaseInBack:
+		r = (easeBack+1)*x*x*x - easeBack*x*x
+	case EaseOutBack:
+		r = 1 + (easeBack+1)*math.Pow(x-1, 3) + easeBack*math.Pow(x-1, 2)
+	case EaseInOutBack:
+		if x < 0.5 {
+			r = (math.Pow(2*x, 2) * ((easeBackInOut+1)*2*x - easeBackInOut)) / 2
+		} else {
+			r = (math.Pow(2*x-2, 2)*((easeBackInOut+1)*(x*2-2)+easeBackInOut) + 2) / 2
+		}
+	case EaseInElastic:
+		r = -math.Pow(2, 10*x-10) * math.Sin((x*10-10.75)*easeElastic)
+	case EaseOutElastic:
+		r = math.Pow(2, -10*x)*math.Sin((x*10-0.75)*easeElastic) + 1
+	case EaseInOutElastic:
+		if x < 0.5 {
+			r = -(math.Pow(2, 20*x-10) * math.Sin((20*x-11.125)*easeElasticInOut)) / 2
+		} else {
+			r = (math.Pow(2, -20*x+10)*math.Sin((20*x-11.125)*easeElasticInOut))/2 + 1
+		}
+	case EaseInBounce:
+		r = 1 - bounce(1-x)
+	case EaseOutBounce:
+		r = bounce(x)
+	case EaseInOutBounce:
+		if x < 0.5 {
+			r = (1 - bounce(1-2*x)) / 2
+		} else {
+			r = (1 + bounce(2*x-1)) / 2
+		}
+	default:
+		r = x
+	}
+	return float32(r)
+}
+
+// inOut accelerates until halfway and then decelerates with a given power
+func inOut(x, power float64) float64 {
+	if x < 0.5 {
+		return math.Pow(2, power-1) * math.Pow(x, power)
+	}
+	return 1 - math.Pow(-2*x+2, power)/2
+}
+
+// bounce is the out bounce curve
+func bounce(x float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case x < 1/d:
+		return n * x * x
+	case x < 2/d:
+		x -= 1.5 / d
+		return n*x*x + 0.75
+	case x < 2.5/d:
+		x -= 2.25 / d
+		return n*x*x + 0.9375
+	default:
+		x -= 2.625 / d
+		return n*x*x + 0.984375
+	}
+}
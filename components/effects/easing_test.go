@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package effects
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestEasingEndpoints(t *testing.T) {
+	for e := EaseLinear; e <= EaseInOutBounce; e++ {
+		for _, tc := range []struct {
+			t, want float32
+		}{
+			{-1, 0},
+			{0, 0},
+			{1, 1},
+			{2, 1},
+		} {
+			if got := e.Apply(tc.t); got != tc.want {
+				t.Errorf("easing %d Apply(%v) = %v, want %v", e, tc.t, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestEasingMidpoints(t *testing.T) {
+	tests := []struct {
+		easing Easing
+		t      float32
+		want   float64
+	}{
+		{EaseLinear, 0.25, 0.25},
+		{EaseInQuad, 0.5, 0.25},
+		{EaseOutQuad, 0.5, 0.75},
+		{EaseInOutQuad, 0.25, 0.125},
+		{EaseInOutQuad, 0.5, 0.5},
+		{EaseInCubic, 0.5, 0.125},
+		{EaseOutCubic, 0.5, 0.875},
+		{EaseInOutCubic, 0.75, 0.9375},
+		{EaseInQuart, 0.5, 0.0625},
+		{EaseInQuint, 0.5, 0.03125},
+		{EaseInSine, 0.5, 1 - math.Sqrt2/2},
+		{EaseOutSine, 0.5, math.Sqrt2 / 2},
+		{EaseInOutSine, 0.5, 0.5},
+		{EaseInExpo, 0.5, 0.03125},
+		{EaseOutExpo, 0.5, 0.96875},
+		{EaseInOutExpo, 0.5, 0.5},
+		{EaseInCirc, 0.5, 1 - math.Sqrt(0.75)},
+		{EaseOutCirc, 0.5, math.Sqrt(0.75)},
+		{EaseInBack, 0.5, -0.0876975},
+		{EaseOutBack, 0.5, 1.0876975},
+		{EaseInOutBack, 0.5, 0.5},
+		{EaseInOutElastic, 0.5, 0.5},
+		{EaseOutBounce, 0.5, 0.765625},
+		{EaseInBounce, 0.5, 0.234375},
+		{EaseInOutBounce, 0.5, 0.5},
+	}
+	for _, tc := range tests {
+		if got := tc.easing.Apply(tc.t); math.Abs(float64(got)-tc.want) > 1e-4 {
+			t.Errorf("easing %d Apply(%v) = %v, want %v", tc.easing, tc.t, got, tc.want)
+		}
+	}
+}
+
+func TestEasingUnmarshalJSON(t *testing.T) {
+	if len(easingNames) != int(EaseInOutBounce)+1 {
+		t.Fatalf("got %d easing names, want %d", len(easingNames), EaseInOutBounce+1)
+	}
+	tests := []struct {
+		json string
+		want Easing
+		err  bool
+	}{
+		{`"linear"`, EaseLinear, false},
+		{`"inOutQuad"`, EaseInOutQuad, false},
+		{`"EaseOutBounce"`, EaseOutBounce, false},
+		{`"outelastic"`, EaseOutElastic, false},
+		{`7`, EaseInQuart, false},
+		{`"wobble"`, EaseLinear, true},
+		{`true`, EaseLinear, true},
+	}
+	for _, tc := range tests {
+		var got Easing
+		err := json.Unmarshal([]byte(tc.json), &got)
+		if (err != nil) != tc.err {
+			t.Errorf("unmarshal %s error = %v, want error %v", tc.json, err, tc.err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("unmarshal %s = %d, want %d", tc.json, got, tc.want)
+		}
+	}
+}
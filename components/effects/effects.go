@@ -26,6 +26,7 @@ package effects
 import (
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/geometry"
 )
 
 // AlternateColorState is the state for an effect
@@ -57,6 +58,7 @@ func (a AlternateColor) Type() goecs.ComponentType {
 // Layer effect is use to render thins in a logical layer
 type Layer struct {
 	Depth float32 // Depth is the screen depth for this effects.Layer
+	Fixed bool    // Fixed indicates that camera effects, like screen shake, do not move this layer, ex: an UI layer
 }
 
 // Type return this goecs.ComponentType
@@ -72,6 +74,250 @@ func (h Hide) Type() goecs.ComponentType {
 	return TYPE.Hide
 }
 
+// BlendMode is how an entity is blended with what is already drawn
+type BlendMode int
+
+//goland:noinspection GoUnusedConst
+const (
+	AlphaBlend    = BlendMode(iota) // AlphaBlend blends considering alpha, the default
+	AdditiveBlend                   // AdditiveBlend adds the colors, useful for glows and lights
+	MultiplyBlend                   // MultiplyBlend multiplies the colors, useful for shadows
+	SubtractBlend                   // SubtractBlend draws the colors minus what is already drawn
+)
+
+// Blend effect is use to draw an entity with a BlendMode
+type Blend struct {
+	Mode BlendMode // Mode is the BlendMode to use
+}
+
+// Type return this goecs.ComponentType
+func (b Blend) Type() goecs.ComponentType {
+	return TYPE.Blend
+}
+
+// Clip effect defines a screen area, placed in the entity geometry.Point, where the entities that are
+// effects.Clipped to it will be drawn, anything outside that area will not be drawn
+type Clip struct {
+	Group string        // Group is the name of this clip group, that could be use by effects.Clipped
+	Size  geometry.Size // Size is the size of the screen area
+}
+
+// Type return this goecs.ComponentType
+func (c Clip) Type() goecs.ComponentType {
+	return TYPE.Clip
+}
+
+// Clipped indicates that this entity should be drawn inside an effects.Clip, if Parent is set it will use the
+// effects.Clip from that entity, otherwise will use the effects.Clip for the given Group
+type Clipped struct {
+	Group  string         // Group is the name of the effects.Clip group
+	Parent goecs.EntityID // Parent is the entity that has the effects.Clip
+}
+
+// Type return this goecs.ComponentType
+func (c Clipped) Type() goecs.ComponentType {
+	return TYPE.Clipped
+}
+
+// YSort effect makes entities in the same effects.Layer to be draw sorted by their Y plus an Offset, so entities
+// lower on the screen are draw in front of the ones above them
+type YSort struct {
+	Offset float32 // Offset is added to the entity Y when sorting, ex: the distance to the entity feet
+}
+
+// Type return this goecs.ComponentType
+func (y YSort) Type() goecs.ComponentType {
+	return TYPE.YSort
+}
+
+// Parallax effect scrolls a sprite.Sprite, tiling it to cover the screen, relative to the movement of a Camera entity
+// multiplied by a Factor, or with a given Velocity
+type Parallax struct {
+	Camera   goecs.EntityID // Camera is the entity that geometry.Point movement drives the scroll, 0 for none
+	Factor   geometry.Point // Factor is how much we scroll relative to the Camera movement, ex: 0.5 half the movement
+	Velocity geometry.Point // Velocity is how many pixels per second we scroll
+	WrapX    bool           // WrapX indicates if the sprite.Sprite should repeat horizontally
+	WrapY    bool           // WrapY indicates if the sprite.Sprite should repeat vertically
+}
+
+// Type return this goecs.ComponentType
+func (p Parallax) Type() goecs.ComponentType {
+	return TYPE.Parallax
+}
+
+// ParallaxState is the state for an effects.Parallax
+type ParallaxState struct {
+	Offset geometry.Point // Offset is how much we have scrolled
+	Camera geometry.Point // Camera is the last position of the camera entity
+}
+
+// Type return this goecs.ComponentType
+func (p ParallaxState) Type() goecs.ComponentType {
+	return TYPE.ParallaxState
+}
+
+// TweenProperty is the property that an effects.Tween animates
+type TweenProperty int
+
+//goland:noinspection GoUnusedConst
+const (
+	TweenPosition = TweenProperty(iota) // TweenPosition animates the geometry.Point from FromPoint to ToPoint
+	TweenScale                          // TweenScale animates the Scale of a sprite.Sprite, ui.SpriteButton or shape
+	TweenRotation                       // TweenRotation animates the Rotation of a sprite.Sprite
+	TweenAlpha                          // TweenAlpha animates the alpha, from 0 to 255, of the color.Solid
+	TweenTint                           // TweenTint animates the color.Solid from FromColor to ToColor
+	TweenProgress                       // TweenProgress animates the Current value of a ui.ProgressBar
+	TweenCustom                         // TweenCustom animates a value using the Getter and Setter
+)
+
+// Tween effect animates a property of an entity from a value to another in a given Time following an Easing, when
+// it finish an events.TweenFinishedEvent will be trigger and the tween will be removed from the entity, or replaced
+// with the next tween in Then
+type Tween struct {
+	Property    TweenProperty                          // Property is the TweenProperty to animate
+	Target      goecs.EntityID                         // Target is the entity to animate, 0 for this entity
+	From        float32                                // From is the starting value for single value properties
+	To          float32                                // To is the final value for single value properties
+	FromPoint   geometry.Point                         // FromPoint is the starting geometry.Point for TweenPosition
+	ToPoint     geometry.Point                         // ToPoint is the final geometry.Point for TweenPosition
+	FromColor   color.Solid                            // FromColor is the starting color.Solid for TweenTint
+	ToColor     color.Solid                            // ToColor is the final color.Solid for TweenTint
+	FromCurrent bool                                   // FromCurrent starts from the property value when it start
+	Time        float32                                // Time is how long it takes to go From To, in seconds
+	Delay       float32                                // Delay is how long to wait before starting, in seconds
+	Ease        Easing                                 // Ease is the Easing curve, EaseLinear by default
+	Repeat      int                                    // Repeat is how many times to play it again, -1 for ever
+	Yoyo        bool                                   // Yoyo plays every repeat in the opposite direction
+	Getter      func(ent *goecs.Entity) float32        // Getter returns the value for TweenCustom
+	Setter      func(ent *goecs.Entity, value float32) // Setter changes the value for TweenCustom
+	Event       interface{}                            // Event is an additional event to trigger when finished
+	Then        []Tween                                // Then are tweens to play one after another when finished
+}
+
+// Type return this goecs.ComponentType
+func (t Tween) Type() goecs.ComponentType {
+	return TYPE.Tween
+}
+
+// TweenState is the state for an effects.Tween
+type TweenState struct {
+	Waited   float32 // Waited is how long we have wait for the Delay
+	Started  bool    // Started indicates if the tween has started
+	Elapsed  float32 // Elapsed is the time that the current play has been running
+	Played   int     // Played is how many times the tween has been repeated
+	Backward bool    // Backward indicates that we are going To From
+}
+
+// Type return this goecs.ComponentType
+func (t TweenState) Type() goecs.ComponentType {
+	return TYPE.TweenState
+}
+
+// Fade effect changes the alpha of the entity colors to a value in a given Time, sprites without color are faded
+// from white, when it finish the colors keep that alpha, an events.ColorEffectFinishedEvent will be trigger and the
+// effect removed
+type Fade struct {
+	To    uint8       // To is the final alpha, 0 to fade out and 255 to fade in
+	Time  float32     // Time is how long it takes to fade, in seconds
+	Ease  Easing      // Ease is the Easing curve, EaseLinear by default
+	Event interface{} // Event is an additional event to trigger when finished
+}
+
+// Type return this goecs.ComponentType
+func (f Fade) Type() goecs.ComponentType {
+	return TYPE.Fade
+}
+
+// FadeState is the state for an effects.Fade
+type FadeState struct {
+	Elapsed  float32 // Elapsed is the time that the effect has been running
+	Progress float32 // Progress is how much the alpha has changed, from 0 to 1, when the entity is draw
+}
+
+// Type return this goecs.ComponentType
+func (f FadeState) Type() goecs.ComponentType {
+	return TYPE.FadeState
+}
+
+// Flash effect tints the entity colors with a Color that fades away in a given Time, ex: when the entity is hit,
+// the tint is only applied when the entity is draw, when it finish an events.ColorEffectFinishedEvent will be
+// trigger and the effect removed
+type Flash struct {
+	Color color.Solid // Color is the flash color.Solid, its alpha is not used
+	Time  float32     // Time is how long the flash last, in seconds
+	Ease  Easing      // Ease is the Easing curve for the flash to fade away, EaseLinear by default
+	Event interface{} // Event is an additional event to trigger when finished
+}
+
+// Type return this goecs.ComponentType
+func (f Flash) Type() goecs.ComponentType {
+	return TYPE.Flash
+}
+
+// FlashState is the state for an effects.Flash
+type FlashState struct {
+	Elapsed float32 // Elapsed is the time that the effect has been running
+	Amount  float32 // Amount is how much of the Color, from 0 to 1, tints the entity colors when is draw
+}
+
+// Type return this goecs.ComponentType
+func (f FlashState) Type() goecs.ComponentType {
+	return TYPE.FlashState
+}
+
+// Pulse effect tints the entity colors with a Color and back, once per Time, during a Duration, the tint is only
+// applied when the entity is draw, when it finish an events.ColorEffectFinishedEvent will be trigger and the effect
+// removed
+type Pulse struct {
+	Color    color.Solid // Color is the pulse color.Solid, its alpha is not used
+	Time     float32     // Time is how long each pulse takes, in seconds
+	Duration float32     // Duration is how long the effect last, in seconds, 0 for ever
+	Ease     Easing      // Ease is the Easing curve for going to the Color and back, EaseLinear by default
+	Event    interface{} // Event is an additional event to trigger when finished
+}
+
+// Type return this goecs.ComponentType
+func (p Pulse) Type() goecs.ComponentType {
+	return TYPE.Pulse
+}
+
+// PulseState is the state for an effects.Pulse
+type PulseState struct {
+	Elapsed float32 // Elapsed is the time that the effect has been running
+	Amount  float32 // Amount is how much of the Color, from 0 to 1, tints the entity colors when is draw
+}
+
+// Type return this goecs.ComponentType
+func (p PulseState) Type() goecs.ComponentType {
+	return TYPE.PulseState
+}
+
+// Blink effect hides and shows the entity, adding and removing an effects.Hide, once per Time, during a Duration,
+// when it finish the entity is show, unless it was hidden before, an events.ColorEffectFinishedEvent will be trigger
+// and the effect removed
+type Blink struct {
+	Time     float32     // Time is how long each hide and show takes, in seconds
+	Duration float32     // Duration is how long the effect last, in seconds, 0 for ever
+	Ease     Easing      // Ease changes the blink rate over the Duration, ex: EaseInQuad blinks faster at the end
+	Event    interface{} // Event is an additional event to trigger when finished
+}
+
+// Type return this goecs.ComponentType
+func (b Blink) Type() goecs.ComponentType {
+	return TYPE.Blink
+}
+
+// BlinkState is the state for an effects.Blink
+type BlinkState struct {
+	Elapsed float32 // Elapsed is the time that the effect has been running
+	Hidden  bool    // Hidden indicates if the entity was hidden when the effect started
+}
+
+// Type return this goecs.ComponentType
+func (b BlinkState) Type() goecs.ComponentType {
+	return TYPE.BlinkState
+}
+
 type types struct {
 	// AlternateColorState is the goecs.ComponentType for effects.AlternateColorState
 	AlternateColorState goecs.ComponentType
@@ -81,6 +327,38 @@ type types struct {
 	Layer goecs.ComponentType
 	// Hide is the goecs.ComponentType for effects.Hide
 	Hide goecs.ComponentType
+	// Blend is the goecs.ComponentType for effects.Blend
+	Blend goecs.ComponentType
+	// Clip is the goecs.ComponentType for effects.Clip
+	Clip goecs.ComponentType
+	// Clipped is the goecs.ComponentType for effects.Clipped
+	Clipped goecs.ComponentType
+	// YSort is the goecs.ComponentType for effects.YSort
+	YSort goecs.ComponentType
+	// Parallax is the goecs.ComponentType for effects.Parallax
+	Parallax goecs.ComponentType
+	// ParallaxState is the goecs.ComponentType for effects.ParallaxState
+	ParallaxState goecs.ComponentType
+	// Tween is the goecs.ComponentType for effects.Tween
+	Tween goecs.ComponentType
+	// TweenState is the goecs.ComponentType for effects.TweenState
+	TweenState goecs.ComponentType
+	// Fade is the goecs.ComponentType for effects.Fade
+	Fade goecs.ComponentType
+	// FadeState is the goecs.ComponentType for effects.FadeState
+	FadeState goecs.ComponentType
+	// Flash is the goecs.ComponentType for effects.Flash
+	Flash goecs.ComponentType
+	// FlashState is the goecs.ComponentType for effects.FlashState
+	FlashState goecs.ComponentType
+	// Pulse is the goecs.ComponentType for effects.Pulse
+	Pulse goecs.ComponentType
+	// PulseState is the goecs.ComponentType for effects.PulseState
+	PulseState goecs.ComponentType
+	// Blink is the goecs.ComponentType for effects.Blink
+	Blink goecs.ComponentType
+	// BlinkState is the goecs.ComponentType for effects.BlinkState
+	BlinkState goecs.ComponentType
 }
 
 // TYPE hold the goecs.ComponentType for our effects components
@@ -89,6 +367,22 @@ var TYPE = types{
 	AlternateColor:      goecs.NewComponentType(),
 	Layer:               goecs.NewComponentType(),
 	Hide:                goecs.NewComponentType(),
+	Blend:               goecs.NewComponentType(),
+	Clip:                goecs.NewComponentType(),
+	Clipped:             goecs.NewComponentType(),
+	YSort:               goecs.NewComponentType(),
+	Parallax:            goecs.NewComponentType(),
+	ParallaxState:       goecs.NewComponentType(),
+	Tween:               goecs.NewComponentType(),
+	TweenState:          goecs.NewComponentType(),
+	Fade:                goecs.NewComponentType(),
+	FadeState:           goecs.NewComponentType(),
+	Flash:               goecs.NewComponentType(),
+	FlashState:          goecs.NewComponentType(),
+	Pulse:               goecs.NewComponentType(),
+	PulseState:          goecs.NewComponentType(),
+	Blink:               goecs.NewComponentType(),
+	BlinkState:          goecs.NewComponentType(),
 }
 
 type gets struct {
@@ -100,6 +394,38 @@ type gets struct {
 	Layer func(e *goecs.Entity) Layer
 	// Hide gets a Hide from a goecs.Entity
 	Hide func(e *goecs.Entity) Hide
+	// Blend gets a Blend from a goecs.Entity
+	Blend func(e *goecs.Entity) Blend
+	// Clip gets a Clip from a goecs.Entity
+	Clip func(e *goecs.Entity) Clip
+	// Clipped gets a Clipped from a goecs.Entity
+	Clipped func(e *goecs.Entity) Clipped
+	// YSort gets a YSort from a goecs.Entity
+	YSort func(e *goecs.Entity) YSort
+	// Parallax gets a Parallax from a goecs.Entity
+	Parallax func(e *goecs.Entity) Parallax
+	// ParallaxState gets a ParallaxState from a goecs.Entity
+	ParallaxState func(e *goecs.Entity) ParallaxState
+	// Tween gets a Tween from a goecs.Entity
+	Tween func(e *goecs.Entity) Tween
+	// TweenState gets a TweenState from a goecs.Entity
+	TweenState func(e *goecs.Entity) TweenState
+	// Fade gets a Fade from a goecs.Entity
+	Fade func(e *goecs.Entity) Fade
+	// FadeState gets a FadeState from a goecs.Entity
+	FadeState func(e *goecs.Entity) FadeState
+	// Flash gets a Flash from a goecs.Entity
+	Flash func(e *goecs.Entity) Flash
+	// FlashState gets a FlashState from a goecs.Entity
+	FlashState func(e *goecs.Entity) FlashState
+	// Pulse gets a Pulse from a goecs.Entity
+	Pulse func(e *goecs.Entity) Pulse
+	// PulseState gets a PulseState from a goecs.Entity
+	PulseState func(e *goecs.Entity) PulseState
+	// Blink gets a Blink from a goecs.Entity
+	Blink func(e *goecs.Entity) Blink
+	// BlinkState gets a BlinkState from a goecs.Entity
+	BlinkState func(e *goecs.Entity) BlinkState
 }
 
 // Get effect component
@@ -120,4 +446,68 @@ var Get = gets{
 	Hide: func(e *goecs.Entity) Hide {
 		return e.Get(TYPE.Hide).(Hide)
 	},
+	// Blend gets a Blend from a goecs.Entity
+	Blend: func(e *goecs.Entity) Blend {
+		return e.Get(TYPE.Blend).(Blend)
+	},
+	// Clip gets a Clip from a goecs.Entity
+	Clip: func(e *goecs.Entity) Clip {
+		return e.Get(TYPE.Clip).(Clip)
+	},
+	// Clipped gets a Clipped from a goecs.Entity
+	Clipped: func(e *goecs.Entity) Clipped {
+		return e.Get(TYPE.Clipped).(Clipped)
+	},
+	// YSort gets a YSort from a goecs.Entity
+	YSort: func(e *goecs.Entity) YSort {
+		return e.Get(TYPE.YSort).(YSort)
+	},
+	// Parallax gets a Parallax from a goecs.Entity
+	Parallax: func(e *goecs.Entity) Parallax {
+		return e.Get(TYPE.Parallax).(Parallax)
+	},
+	// ParallaxState gets a ParallaxState from a goecs.Entity
+	ParallaxState: func(e *goecs.Entity) ParallaxState {
+		return e.Get(TYPE.ParallaxState).(ParallaxState)
+	},
+	// Tween gets a Tween from a goecs.Entity
+	Tween: func(e *goecs.Entity) Tween {
+		return e.Get(TYPE.Tween).(Tween)
+	},
+	// TweenState gets a TweenState from a goecs.Entity
+	TweenState: func(e *goecs.Entity) TweenState {
+		return e.Get(TYPE.TweenState).(TweenState)
+	},
+	// Fade gets a Fade from a goecs.Entity
+	Fade: func(e *goecs.Entity) Fade {
+		return e.Get(TYPE.Fade).(Fade)
+	},
+	// FadeState gets a FadeState from a goecs.Entity
+	FadeState: func(e *goecs.Entity) FadeState {
+		return e.Get(TYPE.FadeState).(FadeState)
+	},
+	// Flash gets a Flash from a goecs.Entity
+	Flash: func(e *goecs.Entity) Flash {
+		return e.Get(TYPE.Flash).(Flash)
+	},
+	// FlashState gets a FlashState from a goecs.Entity
+	FlashState: func(e *goecs.Entity) FlashState {
+		return e.Get(TYPE.FlashState).(FlashState)
+	},
+	// Pulse gets a Pulse from a goecs.Entity
+	Pulse: func(e *goecs.Entity) Pulse {
+		return e.Get(TYPE.Pulse).(Pulse)
+	},
+	// PulseState gets a PulseState from a goecs.Entity
+	PulseState: func(e *goecs.Entity) PulseState {
+		return e.Get(TYPE.PulseState).(PulseState)
+	},
+	// Blink gets a Blink from a goecs.Entity
+	Blink: func(e *goecs.Entity) Blink {
+		return e.Get(TYPE.Blink).(Blink)
+	},
+	// BlinkState gets a BlinkState from a goecs.Entity
+	BlinkState: func(e *goecs.Entity) BlinkState {
+		return e.Get(TYPE.BlinkState).(BlinkState)
+	},
 }
@@ -153,6 +153,26 @@ func (r Rect) Collides(other Rect) bool {
 	return r.collidesOneDirection(other) || other.collidesOneDirection(r)
 }
 
+// Intersection return the geometry.Rect that is inside both this geometry.Rect and the other geometry.Rect, if they
+// do not overlap it will have an empty geometry.Size
+func (r Rect) Intersection(other Rect) Rect {
+	from := Point{
+		X: float32(math.Max(float64(r.From.X), float64(other.From.X))),
+		Y: float32(math.Max(float64(r.From.Y), float64(other.From.Y))),
+	}
+	to := Point{
+		X: float32(math.Min(float64(r.From.X+r.Size.Width), float64(other.From.X+other.Size.Width))),
+		Y: float32(math.Min(float64(r.From.Y+r.Size.Height), float64(other.From.Y+other.Size.Height))),
+	}
+	return Rect{
+		From: from,
+		Size: Size{
+			Width:  float32(math.Max(0, float64(to.X-from.X))),
+			Height: float32(math.Max(0, float64(to.Y-from.Y))),
+		},
+	}
+}
+
 type types struct {
 	// Point is the goecs.ComponentType for geometry.Point
 	Point goecs.ComponentType
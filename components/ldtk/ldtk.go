@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+// Package ldtk has the components for adding LDtk levels to our game
+package ldtk
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/geometry"
+)
+
+// Level is a level of a LDtk project
+type Level struct {
+	Project string  // Project is the LDtk project file
+	Name    string  // Name is the level identifier
+	Scale   float32 // Scale is the level scale
+}
+
+// Type return this goecs.ComponentType
+func (l Level) Type() goecs.ComponentType {
+	return TYPE.Level
+}
+
+// LevelState is the state for ldtk.Level
+type LevelState struct {
+	Position geometry.Point // Position is the level position
+	Scale    float32        // Scale is the level scale
+}
+
+// Type return this goecs.ComponentType
+func (ls LevelState) Type() goecs.ComponentType {
+	return TYPE.LevelState
+}
+
+// TileInfo contains the info for a tile of a ldtk.Level
+type TileInfo struct {
+	Project string // Project is the LDtk project file
+	Level   string // Level is the level identifier
+	Layer   string // Layer is the layer identifier
+	Col     int    // Col is the col of this tile in the layer
+	Row     int    // Row is the row of this tile in the layer
+	ID      int    // ID is the tile id in its tileset
+}
+
+// Type return this goecs.ComponentType
+func (ti TileInfo) Type() goecs.ComponentType {
+	return TYPE.TileInfo
+}
+
+// EntityInfo contains the info for an entity instance in a ldtk.Level
+type EntityInfo struct {
+	IID        string                 // IID is the entity instance unique id
+	Identifier string                 // Identifier is the entity definition identifier
+	Project    string                 // Project is the LDtk project file
+	Level      string                 // Level is the level identifier
+	Layer      string                 // Layer is the layer identifier
+	Size       geometry.Size          // Size is the entity size, scaled to the level scale
+	Pivot      geometry.Point         // Pivot is the relative pivot 0..1 in each axis of the entity position
+	Tags       []string               // Tags are the entity definition tags
+	Fields     map[string]interface{} // Fields are the entity field values by identifier, as read from the JSON
+}
+
+// Type return this goecs.ComponentType
+func (ei EntityInfo) Type() goecs.ComponentType {
+	return TYPE.EntityInfo
+}
+
+// EntityFactory is a function that is call when an entity instance of a given identifier is added to the world, the
+// entity already has its ldtk.EntityInfo and geometry.Point so the factory could add other components to it
+type EntityFactory func(world *goecs.World, ent *goecs.Entity) error
+
+type types struct {
+	// Level is the goecs.ComponentType for ldtk.Level
+	Level goecs.ComponentType
+	// LevelState is the goecs.ComponentType for ldtk.LevelState
+	LevelState goecs.ComponentType
+	// TileInfo is the goecs.ComponentType for ldtk.TileInfo
+	TileInfo goecs.ComponentType
+	// EntityInfo is the goecs.ComponentType for ldtk.EntityInfo
+	EntityInfo goecs.ComponentType
+}
+
+// TYPE hold the goecs.ComponentType for our ldtk components
+var TYPE = types{
+	Level:      goecs.NewComponentType(),
+	LevelState: goecs.NewComponentType(),
+	TileInfo:   goecs.NewComponentType(),
+	EntityInfo: goecs.NewComponentType(),
+}
+
+type gets struct {
+	// Level gets a ldtk.Level from a goecs.Entity
+	Level func(e *goecs.Entity) Level
+	// LevelState gets a ldtk.LevelState from a goecs.Entity
+	LevelState func(e *goecs.Entity) LevelState
+	// TileInfo gets a ldtk.TileInfo from a goecs.Entity
+	TileInfo func(e *goecs.Entity) TileInfo
+	// EntityInfo gets a ldtk.EntityInfo from a goecs.Entity
+	EntityInfo func(e *goecs.Entity) EntityInfo
+}
+
+// Get a ldtk component
+var Get = gets{
+	// Level gets a ldtk.Level from a goecs.Entity
+	Level: func(e *goecs.Entity) Level {
+		return e.Get(TYPE.Level).(Level)
+	},
+	// LevelState gets a ldtk.LevelState from a goecs.Entity
+	LevelState: func(e *goecs.Entity) LevelState {
+		return e.Get(TYPE.LevelState).(LevelState)
+	},
+	// TileInfo gets a ldtk.TileInfo from a goecs.Entity
+	TileInfo: func(e *goecs.Entity) TileInfo {
+		return e.Get(TYPE.TileInfo).(TileInfo)
+	},
+	// EntityInfo gets a ldtk.EntityInfo from a goecs.Entity
+	EntityInfo: func(e *goecs.Entity) EntityInfo {
+		return e.Get(TYPE.EntityInfo).(EntityInfo)
+	},
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+// Package skeleton has the components for cutout animations, sprite parts attached to a hierarchy of bones
+package skeleton
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+)
+
+// Transform is a position, rotation and scale relative to a parent
+type Transform struct {
+	Position geometry.Point // Position is the offset from the parent
+	Rotation float32        // Rotation in degrees, clockwise
+	Scale    float32        // Scale is the scale, 0 is the same as 1
+}
+
+// Bone is a node in a Skeleton, the root bones are relative to the entity geometry.Point
+type Bone struct {
+	Name   string    // Name is the bone name
+	Parent string    // Parent is the name of the parent bone, empty for a root bone
+	Local  Transform // Local is the setup transform relative to the parent
+}
+
+// Part is a sprite.Sprite draw attached to a Bone, the bone transform is applied around the sprite pivot
+type Part struct {
+	Bone   string    // Bone is the name of the bone that this part follows
+	Sprite string    // Sprite is the name of the sprite in the Skeleton Sheet
+	Local  Transform // Local is the transform relative to the bone
+}
+
+// Property is the property of a Bone that a Timeline animates
+type Property int
+
+//goland:noinspection GoUnusedConst
+const (
+	Rotate    = Property(iota) // Rotate adds the key X, in degrees, to the bone rotation
+	Translate                  // Translate adds the key geometry.Point to the bone position
+	Scale                      // Scale multiplies the bone scale by the key X
+)
+
+// Key is a value of a Timeline at a given time
+type Key struct {
+	Time    float32        // Time in seconds from the start of the Clip
+	Value   geometry.Point // Value is the value for the Timeline Property
+	Ease    effects.Easing // Ease is the effects.Easing curve to the next key
+	Stepped bool           // Stepped keeps this value until the next key, without interpolation
+}
+
+// Timeline animates a Property of a Bone with Keys, sorted by time
+type Timeline struct {
+	Bone     string   // Bone is the name of the bone to animate
+	Property Property // Property is the Property to animate
+	Keys     []Key    // Keys are the values over time
+}
+
+// Clip is an animation of a Skeleton
+type Clip struct {
+	Duration  float32    // Duration is how long the clip is, in seconds
+	Once      bool       // Once plays the clip one time and stays at its end, otherwise it loops
+	Timelines []Timeline // Timelines are the bone animations
+}
+
+// Skeleton is a hierarchy of bones that moves sprite parts, with clips that animate the bones, the parts are added
+// as entities with a PartInfo and follow the skeleton
+type Skeleton struct {
+	Sheet   string          // Sheet is the sprite sheet that has the parts sprites
+	Bones   []Bone          // Bones are the skeleton bones, parents before its children
+	Parts   []Part          // Parts are the sprites in draw order
+	Clips   map[string]Clip // Clips are the skeleton animations by name
+	Current string          // Current is the clip to play, empty for the setup pose
+	Speed   float32         // Speed is a multiplier of the clips speed
+	Scale   float32         // Scale is the skeleton scale
+	FlipX   bool            // FlipX indicates if the skeleton is flipped in the X-Assis
+	FlipY   bool            // FlipY indicates if the skeleton is flipped in the Y-Assis
+}
+
+// Type return this goecs.ComponentType
+func (s Skeleton) Type() goecs.ComponentType {
+	return TYPE.Skeleton
+}
+
+// State is the state of a Skeleton
+type State struct {
+	Current string           // Current is the clip that is running
+	Time    float32          // Time is the time in the current clip
+	Ended   bool             // Ended indicates that a Once clip has finished
+	Parts   []goecs.EntityID // Parts are the entities for each skeleton Part
+}
+
+// Type return this goecs.ComponentType
+func (s State) Type() goecs.ComponentType {
+	return TYPE.State
+}
+
+// PartInfo is added to the entity of each Part of a Skeleton
+type PartInfo struct {
+	Skeleton goecs.EntityID // Skeleton is the entity that has the Skeleton
+	Bone     string         // Bone is the name of the bone that this part follows
+	Sprite   string         // Sprite is the sprite name
+}
+
+// Type return this goecs.ComponentType
+func (p PartInfo) Type() goecs.ComponentType {
+	return TYPE.PartInfo
+}
+
+type types struct {
+	// Skeleton is the goecs.ComponentType for skeleton.Skeleton
+	Skeleton goecs.ComponentType
+	// State is the goecs.ComponentType for skeleton.State
+	State goecs.ComponentType
+	// PartInfo is the goecs.ComponentType for skeleton.PartInfo
+	PartInfo goecs.ComponentType
+}
+
+// TYPE hold the goecs.ComponentType for our skeleton components
+var TYPE = types{
+	Skeleton: goecs.NewComponentType(),
+	State:    goecs.NewComponentType(),
+	PartInfo: goecs.NewComponentType(),
+}
+
+type gets struct {
+	// Skeleton gets a skeleton.Skeleton from a goecs.Entity
+	Skeleton func(e *goecs.Entity) Skeleton
+	// State gets a skeleton.State from a goecs.Entity
+	State func(e *goecs.Entity) State
+	// PartInfo gets a skeleton.PartInfo from a goecs.Entity
+	PartInfo func(e *goecs.Entity) PartInfo
+}
+
+// Get a skeleton component
+var Get = gets{
+	// Skeleton gets a skeleton.Skeleton from a goecs.Entity
+	Skeleton: func(e *goecs.Entity) Skeleton {
+		return e.Get(TYPE.Skeleton).(Skeleton)
+	},
+	// State gets a skeleton.State from a goecs.Entity
+	State: func(e *goecs.Entity) State {
+		return e.Get(TYPE.State).(State)
+	},
+	// PartInfo gets a skeleton.PartInfo from a goecs.Entity
+	PartInfo: func(e *goecs.Entity) PartInfo {
+		return e.Get(TYPE.PartInfo).(PartInfo)
+	},
+}
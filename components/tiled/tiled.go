@@ -30,8 +30,9 @@ import (
 
 // Map is a tiled.Map
 type Map struct {
-	Name  string  // Name of our tiled.Map
-	Scale float32 // Scale is the map scale
+	Name      string  // Name of our tiled.Map
+	Scale     float32 // Scale is the map scale
+	ChunkSize int     // ChunkSize if not 0 the tile layers are draw in chunks of this many cells, not a sprite per tile
 }
 
 // Type return this goecs.ComponentType
@@ -41,8 +42,48 @@ func (m Map) Type() goecs.ComponentType {
 
 // MapState is the state for tiled.Map
 type MapState struct {
-	Position geometry.Point // Position is the map position
-	Scale    float32        // Scale is the map scale
+	Origin     geometry.Point                // Origin is the map position when was added, parallax layers move from it
+	Position   geometry.Point                // Position is the map position
+	Scale      float32                       // Scale is the map scale
+	Animations map[uint32]TileAnimationState // Animations is the state per each animated global block id
+	Chunks     map[ChunkKey][]goecs.EntityID // Chunks are the entities spawned per each chunk of a tiled.Stream map
+}
+
+// ChunkKey identifies a chunk of cells of a tile layer in a tiled.Map
+type ChunkKey struct {
+	Layer int // Layer is the index of the tile layer in the map layers, including the nested ones
+	Col   int // Col is the first col of the chunk
+	Row   int // Row is the first row of the chunk
+}
+
+// Stream indicates that a tiled.Map spawns the tiles of its layers only in the chunks around a focus point, and
+// despawn them when they are far away, the chunks size is the map ChunkSize, or 16 if is 0
+type Stream struct {
+	Focus  goecs.EntityID // Focus is the entity which geometry.Point is the focus, 0 to use Point
+	Point  geometry.Point // Point is the focus in world coordinates, when there is no Focus entity
+	Radius float32        // Radius is the distance from the focus, in world pixels, where chunks are spawned
+}
+
+// Type return this goecs.ComponentType
+func (s Stream) Type() goecs.ComponentType {
+	return TYPE.Stream
+}
+
+// TileAnimationState is the state of the animation for all the tiles with the same global block id
+type TileAnimationState struct {
+	Frame int     // Frame is the current frame
+	Time  float32 // Time is how long the current frame has been show
+}
+
+// AnimatedTile indicates that an entity is an animated tile of a tiled.Map
+type AnimatedTile struct {
+	Map string // Map is the name of the tiled.Map
+	GID uint32 // GID is the global block id with the animation
+}
+
+// Type return this goecs.ComponentType
+func (a AnimatedTile) Type() goecs.ComponentType {
+	return TYPE.AnimatedTile
 }
 
 // Type return this goecs.ComponentType
@@ -56,6 +97,8 @@ type BlockInfo struct {
 	Row        int               // Row is the row of this block in the map
 	Col        int               // Col is the col of this block in the map
 	Layer      string            // Layer is the layer name for this block
+	Map        string            // Map is the name of the tiled.Map that this block belongs to
+	GID        uint32            // GID is the global block id, 0 for an empty block
 }
 
 // Type return this goecs.ComponentType
@@ -63,6 +106,74 @@ func (b BlockInfo) Type() goecs.ComponentType {
 	return TYPE.BlockInfo
 }
 
+// Chunk is an area of cells of a tile layer in a tiled.Map that is draw from the map data, it is positioned by its
+// map entity so moving the map does not need to update it
+type Chunk struct {
+	Map    goecs.EntityID // Map is the tiled.Map entity
+	Layer  int            // Layer is the index of the tile layer in the map layers, including the nested ones
+	Col    int            // Col is the first col of this chunk in the map
+	Row    int            // Row is the first row of this chunk in the map
+	Cells  []int          // Cells are the indexes of the chunk cells in the layer, in render order
+	Bounds geometry.Rect  // Bounds are the area, in map pixels, where the chunk tiles could be draw
+}
+
+// Type return this goecs.ComponentType
+func (c Chunk) Type() goecs.ComponentType {
+	return TYPE.Chunk
+}
+
+// LayerInfo contains the info for an entity that draws a whole layer of a tiled.Map, as an image layer or the map
+// background color
+type LayerInfo struct {
+	Map        string            // Map is the name of the tiled.Map that this layer belongs to
+	Layer      string            // Layer is the layer name, empty for the map background
+	Properties map[string]string // Properties are the properties for this layer
+}
+
+// Type return this goecs.ComponentType
+func (l LayerInfo) Type() goecs.ComponentType {
+	return TYPE.LayerInfo
+}
+
+// ObjectShape is the shape of a tiled object
+type ObjectShape int
+
+//goland:noinspection GoUnusedConst
+const (
+	RectangleShape = ObjectShape(iota) // RectangleShape is a rectangle object with a Size
+	EllipseShape                       // EllipseShape is an ellipse object that fits in its Size
+	PointShape                         // PointShape is an object that is only a position
+	PolygonShape                       // PolygonShape is a closed shape defined by its Points
+	PolylineShape                      // PolylineShape is an open line defined by its Points
+	TileShape                          // TileShape is a tile object, it will have a sprite.Sprite
+	TextShape                          // TextShape is a text object
+)
+
+// ObjectInfo contains the info for an object in a tiled object layer
+type ObjectInfo struct {
+	ID         uint32            // ID is the object id in the map
+	Name       string            // Name is the object name
+	Class      string            // Class is the object type, in newer tiled versions is called class
+	Map        string            // Map is the name of the tiled.Map that this object belongs to
+	Layer      string            // Layer is the object layer name for this object
+	Properties map[string]string // Properties are the properties for this object
+	Shape      ObjectShape       // Shape is the object shape
+	Size       geometry.Size     // Size is the object size, scaled to the map scale
+	Rotation   float32           // Rotation is the object rotation in degrees, clockwise
+	Points     []geometry.Point  // Points are the points of a polygon or polyline, relative to the object position
+	GID        uint32            // GID is the global tile id for a TileShape object
+	Text       string            // Text is the text for a TextShape object
+}
+
+// Type return this goecs.ComponentType
+func (o ObjectInfo) Type() goecs.ComponentType {
+	return TYPE.ObjectInfo
+}
+
+// ObjectFactory is a function that is call when an object of a given type is added to the world, the entity
+// already has its tiled.ObjectInfo and geometry.Point so the factory could add other components to it
+type ObjectFactory func(world *goecs.World, ent *goecs.Entity) error
+
 type types struct {
 	// Map is the goecs.ComponentType for tiled.Map
 	Map goecs.ComponentType
@@ -70,13 +181,28 @@ type types struct {
 	MapState goecs.ComponentType
 	// BlockInfo is the goecs.ComponentType for tiled.BlockInfo
 	BlockInfo goecs.ComponentType
+	// ObjectInfo is the goecs.ComponentType for tiled.ObjectInfo
+	ObjectInfo goecs.ComponentType
+	// AnimatedTile is the goecs.ComponentType for tiled.AnimatedTile
+	AnimatedTile goecs.ComponentType
+	// Chunk is the goecs.ComponentType for tiled.Chunk
+	Chunk goecs.ComponentType
+	// LayerInfo is the goecs.ComponentType for tiled.LayerInfo
+	LayerInfo goecs.ComponentType
+	// Stream is the goecs.ComponentType for tiled.Stream
+	Stream goecs.ComponentType
 }
 
 // TYPE hold the goecs.ComponentType for our tiled components
 var TYPE = types{
-	Map:       goecs.NewComponentType(),
-	MapState:  goecs.NewComponentType(),
-	BlockInfo: goecs.NewComponentType(),
+	Map:          goecs.NewComponentType(),
+	MapState:     goecs.NewComponentType(),
+	BlockInfo:    goecs.NewComponentType(),
+	ObjectInfo:   goecs.NewComponentType(),
+	AnimatedTile: goecs.NewComponentType(),
+	Chunk:        goecs.NewComponentType(),
+	LayerInfo:    goecs.NewComponentType(),
+	Stream:       goecs.NewComponentType(),
 }
 
 type gets struct {
@@ -86,6 +212,16 @@ type gets struct {
 	MapState func(e *goecs.Entity) MapState
 	// BlockInfo gets a tiled.BlockInfo from a goecs.Entity
 	BlockInfo func(e *goecs.Entity) BlockInfo
+	// ObjectInfo gets a tiled.ObjectInfo from a goecs.Entity
+	ObjectInfo func(e *goecs.Entity) ObjectInfo
+	// AnimatedTile gets a tiled.AnimatedTile from a goecs.Entity
+	AnimatedTile func(e *goecs.Entity) AnimatedTile
+	// Chunk gets a tiled.Chunk from a goecs.Entity
+	Chunk func(e *goecs.Entity) Chunk
+	// LayerInfo gets a tiled.LayerInfo from a goecs.Entity
+	LayerInfo func(e *goecs.Entity) LayerInfo
+	// Stream gets a tiled.Stream from a goecs.Entity
+	Stream func(e *goecs.Entity) Stream
 }
 
 // Get a geometry component
@@ -102,4 +238,24 @@ var Get = gets{
 	BlockInfo: func(e *goecs.Entity) BlockInfo {
 		return e.Get(TYPE.BlockInfo).(BlockInfo)
 	},
+	// ObjectInfo gets a tiled.ObjectInfo from a goecs.Entity
+	ObjectInfo: func(e *goecs.Entity) ObjectInfo {
+		return e.Get(TYPE.ObjectInfo).(ObjectInfo)
+	},
+	// AnimatedTile gets a tiled.AnimatedTile from a goecs.Entity
+	AnimatedTile: func(e *goecs.Entity) AnimatedTile {
+		return e.Get(TYPE.AnimatedTile).(AnimatedTile)
+	},
+	// Chunk gets a tiled.Chunk from a goecs.Entity
+	Chunk: func(e *goecs.Entity) Chunk {
+		return e.Get(TYPE.Chunk).(Chunk)
+	},
+	// LayerInfo gets a tiled.LayerInfo from a goecs.Entity
+	LayerInfo: func(e *goecs.Entity) LayerInfo {
+		return e.Get(TYPE.LayerInfo).(LayerInfo)
+	},
+	// Stream gets a tiled.Stream from a goecs.Entity
+	Stream: func(e *goecs.Entity) Stream {
+		return e.Get(TYPE.Stream).(Stream)
+	},
 }
@@ -24,6 +24,8 @@ package ui
 
 import (
 	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/geometry"
 )
 
 // HAlignment horizontal alignment for a Text
@@ -59,3 +61,65 @@ type Text struct {
 func (t Text) Type() goecs.ComponentType {
 	return TYPE.Text
 }
+
+// LocalizedText is a ui.Text that will be translated to the current language
+type LocalizedText struct {
+	Key     string                 // Key is the translation key
+	Context string                 // Context disambiguates keys with the same text, ex: a msgctxt in a PO file
+	Count   int                    // Count is the number used to choose the plural form, and the {count} placeholder
+	Args    map[string]interface{} // Args are the values for the {name} placeholders in the translation
+}
+
+// Type return this goecs.ComponentType
+func (l LocalizedText) Type() goecs.ComponentType {
+	return TYPE.LocalizedText
+}
+
+// LocalizedTextState is the state for a ui.LocalizedText
+type LocalizedTextState struct {
+	Language string        // Language is the language that the text was resolved to
+	Resolved LocalizedText // Resolved is the ui.LocalizedText that has been resolved
+}
+
+// Type return this goecs.ComponentType
+func (l LocalizedTextState) Type() goecs.ComponentType {
+	return TYPE.LocalizedTextState
+}
+
+// TextAnimation is a per character animation for a ui.TextStyle
+type TextAnimation int
+
+// Text animations
+const (
+	NoTextAnimation         = TextAnimation(iota) // NoTextAnimation indicates no ui.TextAnimation
+	WaveTextAnimation                             // WaveTextAnimation moves the characters up and down in a wave
+	ShakeTextAnimation                            // ShakeTextAnimation shakes each character randomly
+	TypewriterTextAnimation                       // TypewriterTextAnimation reveals the characters one by one
+)
+
+// TextStyle adds outline, shadow, gradient and animations to a ui.Text
+type TextStyle struct {
+	Outline          color.Solid    // Outline is the outline color.Solid
+	OutlineThickness float32        // OutlineThickness is the outline thickness in pixels, 0 for no outline
+	Shadow           geometry.Size  // Shadow is the offset of the shadow, 0 for no shadow
+	ShadowColor      color.Solid    // ShadowColor is the shadow color.Solid
+	Gradient         color.Gradient // Gradient is the fill color.Gradient, used if From and To are different
+	Animation        TextAnimation  // Animation is the per character ui.TextAnimation
+	Amplitude        float32        // Amplitude is how many pixels a character moves on a wave or shake
+	Speed            float32        // Speed is cycles per second on wave or shake, characters per second on typewriter
+}
+
+// Type return this goecs.ComponentType
+func (t TextStyle) Type() goecs.ComponentType {
+	return TYPE.TextStyle
+}
+
+// TextStyleState is the state for the animation of a ui.TextStyle
+type TextStyleState struct {
+	Time float32 // Time is how long the animation has been running
+}
+
+// Type return this goecs.ComponentType
+func (t TextStyleState) Type() goecs.ComponentType {
+	return TYPE.TextStyleState
+}
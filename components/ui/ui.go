@@ -60,6 +60,14 @@ type types struct {
 	ProgressBarHoverColor goecs.ComponentType
 	// ControlState is the goecs.ComponentType for ui.ControlState
 	ControlState goecs.ComponentType
+	// TextStyle is the goecs.ComponentType for ui.TextStyle
+	TextStyle goecs.ComponentType
+	// TextStyleState is the goecs.ComponentType for ui.TextStyleState
+	TextStyleState goecs.ComponentType
+	// LocalizedText is the goecs.ComponentType for ui.LocalizedText
+	LocalizedText goecs.ComponentType
+	// LocalizedTextState is the goecs.ComponentType for ui.LocalizedTextState
+	LocalizedTextState goecs.ComponentType
 }
 
 // TYPE hold the goecs.ComponentType for our ui components
@@ -73,6 +81,10 @@ var TYPE = types{
 	ProgressBarColor:      goecs.NewComponentType(),
 	ProgressBarHoverColor: goecs.NewComponentType(),
 	ControlState:          goecs.NewComponentType(),
+	TextStyle:             goecs.NewComponentType(),
+	TextStyleState:        goecs.NewComponentType(),
+	LocalizedText:         goecs.NewComponentType(),
+	LocalizedTextState:    goecs.NewComponentType(),
 }
 
 type gets struct {
@@ -94,6 +106,14 @@ type gets struct {
 	ProgressBarHoverColor func(e *goecs.Entity) ProgressBarHoverColor
 	// ControlState gets a ui.ControlState from a goecs.Entity
 	ControlState func(e *goecs.Entity) ControlState
+	// TextStyle gets a ui.TextStyle from a goecs.Entity
+	TextStyle func(e *goecs.Entity) TextStyle
+	// TextStyleState gets a ui.TextStyleState from a goecs.Entity
+	TextStyleState func(e *goecs.Entity) TextStyleState
+	// LocalizedText gets a ui.LocalizedText from a goecs.Entity
+	LocalizedText func(e *goecs.Entity) LocalizedText
+	// LocalizedTextState gets a ui.LocalizedTextState from a goecs.Entity
+	LocalizedTextState func(e *goecs.Entity) LocalizedTextState
 }
 
 // Get a ui component
@@ -134,4 +154,20 @@ var Get = gets{
 	ControlState: func(e *goecs.Entity) ControlState {
 		return e.Get(TYPE.ControlState).(ControlState)
 	},
+	// TextStyle gets a ui.TextStyle from a goecs.Entity
+	TextStyle: func(e *goecs.Entity) TextStyle {
+		return e.Get(TYPE.TextStyle).(TextStyle)
+	},
+	// TextStyleState gets a ui.TextStyleState from a goecs.Entity
+	TextStyleState: func(e *goecs.Entity) TextStyleState {
+		return e.Get(TYPE.TextStyleState).(TextStyleState)
+	},
+	// LocalizedText gets a ui.LocalizedText from a goecs.Entity
+	LocalizedText: func(e *goecs.Entity) LocalizedText {
+		return e.Get(TYPE.LocalizedText).(LocalizedText)
+	},
+	// LocalizedTextState gets a ui.LocalizedTextState from a goecs.Entity
+	LocalizedTextState: func(e *goecs.Entity) LocalizedTextState {
+		return e.Get(TYPE.LocalizedTextState).(LocalizedTextState)
+	},
 }
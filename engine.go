@@ -26,10 +26,16 @@ import (
 	"fmt"
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/animation"
 	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/cutscene"
 	"github.com/juan-medina/gosge/components/device"
 	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/ldtk"
+	"github.com/juan-medina/gosge/components/skeleton"
 	"github.com/juan-medina/gosge/components/sprite"
+	"github.com/juan-medina/gosge/components/tiled"
+	"github.com/juan-medina/gosge/components/ui"
 	"github.com/juan-medina/gosge/events"
 	"github.com/juan-medina/gosge/managers"
 	"github.com/juan-medina/gosge/options"
@@ -66,6 +72,9 @@ type Engine struct {
 	sm        *managers.StorageManager
 	dm        managers.DeviceManager
 	cm        *managers.CollisionManager
+	lm        managers.Localizer
+	tm        managers.TiledMapper
+	ldm       managers.LDtkLeveler
 	stages    map[string]InitFunc
 }
 
@@ -90,6 +99,64 @@ func (e *Engine) LoadSpriteSheet(fileName string) error {
 	return e.sm.LoadSpriteSheet(fileName)
 }
 
+// LoadGIF preloads an animated GIF as a sprite sheet, returning an animation.Animation that plays it with its own
+// frame delays
+func (e *Engine) LoadGIF(fileName string) (anim animation.Animation, err error) {
+	if err = e.sm.LoadGIF(fileName); err == nil {
+		var sequences map[string]animation.Sequence
+		if sequences, err = e.sm.GetAnimations(fileName); err == nil {
+			anim = animation.Animation{
+				Sequences: sequences,
+				Current:   fileName,
+				Speed:     1,
+			}
+		}
+	}
+	return
+}
+
+// LoadStateMachine preloads an animation.StateMachine from a JSON file
+func (e *Engine) LoadStateMachine(fileName string) error {
+	return e.sm.LoadStateMachine(fileName)
+}
+
+// GetStateMachine returns a new animation.StateMachine, with its own parameters, from a preloaded JSON file
+func (e *Engine) GetStateMachine(fileName string) (animation.StateMachine, error) {
+	return e.sm.GetStateMachine(fileName)
+}
+
+// LoadSkeleton preloads a skeleton.Skeleton from a Spine JSON, its parts should be in a preloaded sprite sheet
+func (e *Engine) LoadSkeleton(fileName string, sheet string) error {
+	return e.sm.LoadSkeleton(fileName, sheet)
+}
+
+// GetSkeleton returns a preloaded skeleton.Skeleton
+func (e *Engine) GetSkeleton(fileName string) (skeleton.Skeleton, error) {
+	return e.sm.GetSkeleton(fileName)
+}
+
+// LoadTimeline preloads a cutscene.Timeline from a JSON file
+func (e *Engine) LoadTimeline(fileName string) error {
+	return e.sm.LoadTimeline(fileName)
+}
+
+// GetTimeline returns a preloaded cutscene.Timeline, that could be played with a cutscene.Player
+func (e *Engine) GetTimeline(fileName string) (cutscene.Timeline, error) {
+	return e.sm.GetTimeline(fileName)
+}
+
+// GetAnimation returns the animation.Sequence for a frame tag of a sprite sheet loaded from an Aseprite JSON, for a
+// GIF the tag is the file name
+func (e *Engine) GetAnimation(sheet string, tag string) (animation.Sequence, error) {
+	return e.sm.GetAnimation(sheet, tag)
+}
+
+// GetAnimations returns all the animation.Sequence, by frame tag, of a sprite sheet loaded from an Aseprite JSON or
+// a GIF, ready to be used in an animation.Animation
+func (e *Engine) GetAnimations(sheet string) (map[string]animation.Sequence, error) {
+	return e.sm.GetAnimations(sheet)
+}
+
 // World returns the game goecs.World
 func (e *Engine) World() *goecs.World {
 	return e.world
@@ -168,11 +235,26 @@ func (e *Engine) prepare() error {
 	// add the music manager
 	e.register(managers.Music(e.dm, e.sm), highPriority)
 
+	// cutscene manager will run after game systems but before the animation system
+	e.register(managers.Cutscenes(), lowPriority)
+
 	// animation system will run after game systems but before the rendering managers
 	e.register(managers.Animation(), lowPriority)
 
+	// skeleton manager will run after game systems but before the rendering managers
+	e.register(managers.Skeletons(), lowPriority)
+
 	// tiled manager will run after game systems but before the rendering managers
-	e.register(managers.TiledMaps(e.sm), lowPriority)
+	e.register(e.tm, lowPriority)
+
+	// LDtk manager will run after game systems but before the rendering managers
+	e.register(e.ldm, lowPriority)
+
+	// localization manager will run after game system but before the ui manager
+	e.register(e.lm, lowPriority)
+
+	// tween manager will run after game system but before the ui manager
+	e.register(managers.Tweens(), lowPriority)
 
 	// ui manager will run after game system but before the effect managers
 	e.register(managers.UI(e.dm, e.cm), lowPriority)
@@ -180,8 +262,12 @@ func (e *Engine) prepare() error {
 	// effects manager will run after game system but before the rendering managers
 	e.register(managers.Effects(), lowPriority)
 
+	// camera effects manager will run after game system but before the rendering managers
+	cem := managers.CameraEffects()
+	e.register(cem, lowPriority)
+
 	// rendering manager will run last
-	e.register(managers.Rendering(e.dm, e.sm), lastPriority)
+	e.register(managers.Rendering(e.dm, e.sm, cem), lastPriority)
 	e.status = statusRunning
 
 	return err
@@ -327,6 +413,105 @@ func (e Engine) GeTiledMapSize(name string) (size geometry.Size, err error) {
 	return
 }
 
+// LoadLocale preloads a locale from a JSON or PO file, the first locale loaded will be the default language
+func (e Engine) LoadLocale(filename string) error {
+	return e.sm.LoadLocale(filename)
+}
+
+// GetLanguage returns the current language
+func (e Engine) GetLanguage() string {
+	return e.lm.Language()
+}
+
+// Localize returns the translation of a key to the current language
+func (e Engine) Localize(key string, count int, args map[string]interface{}) string {
+	return e.lm.Localize(e.lm.Language(), ui.LocalizedText{Key: key, Count: count, Args: args})
+}
+
+// LocalizeContext returns the translation of a key in a context, ex: a msgctxt in a PO file, to the current language
+func (e Engine) LocalizeContext(context, key string, count int, args map[string]interface{}) string {
+	return e.lm.Localize(e.lm.Language(), ui.LocalizedText{Key: key, Context: context, Count: count, Args: args})
+}
+
+// TiledTileToWorld returns the world geometry.Point of the top left corner of a tile cell in a tiled.Map entity
+func (e Engine) TiledTileToWorld(mapID goecs.EntityID, col, row int) (geometry.Point, error) {
+	return e.tm.TileToWorld(e.world, mapID, col, row)
+}
+
+// TiledWorldToTile returns the tile col and row in a world geometry.Point for a tiled.Map entity
+func (e Engine) TiledWorldToTile(mapID goecs.EntityID, at geometry.Point) (col, row int, err error) {
+	return e.tm.WorldToTile(e.world, mapID, at)
+}
+
+// RegisterTiledObjectFactory register a tiled.ObjectFactory that will be call for each object of a given class when
+// a tiled.Map is added to the world
+func (e Engine) RegisterTiledObjectFactory(class string, factory tiled.ObjectFactory) {
+	e.tm.RegisterObjectFactory(class, factory)
+}
+
+// GetTiledTile returns the tiled.BlockInfo for a tile cell in a layer of a tiled.Map entity
+func (e Engine) GetTiledTile(mapID goecs.EntityID, layer string, col, row int) (tiled.BlockInfo, error) {
+	return e.tm.GetTile(e.world, mapID, layer, col, row)
+}
+
+// GetTiledTileAt returns the tiled.BlockInfo for the tile in a world geometry.Point in a layer of a tiled.Map entity
+func (e Engine) GetTiledTileAt(mapID goecs.EntityID, layer string, at geometry.Point) (info tiled.BlockInfo,
+	err error) {
+	var col, row int
+	if col, row, err = e.tm.WorldToTile(e.world, mapID, at); err == nil {
+		info, err = e.tm.GetTile(e.world, mapID, layer, col, row)
+	}
+	return
+}
+
+// SetTiledTile changes a tile cell in a layer of a tiled.Map entity to a global block id, that may include the
+// tiled flip flags, or to 0 to remove the tile
+func (e Engine) SetTiledTile(mapID goecs.EntityID, layer string, col, row int, gid uint32) error {
+	return e.tm.SetTile(e.world, mapID, layer, col, row, gid)
+}
+
+// FindTiledTiles returns the tiled.BlockInfo for the tiles in a layer of a tiled.Map entity with a given property
+// value, if the layer is empty all the layers will be searched
+func (e Engine) FindTiledTiles(mapID goecs.EntityID, layer, property, value string) ([]tiled.BlockInfo, error) {
+	return e.tm.FindTiles(e.world, mapID, layer, property, value)
+}
+
+// LoadLDtkProject preload a LDtk project, its levels could be added to the world with a ldtk.Level
+func (e Engine) LoadLDtkProject(filename string) error {
+	return e.sm.LoadLDtkProject(filename)
+}
+
+// GetLDtkLevelSize returns the geometry.Size of a level in a LDtk project
+func (e Engine) GetLDtkLevelSize(project, level string) (size geometry.Size, err error) {
+	var def components.LDtkLevelDef
+	if def, err = e.sm.GetLDtkLevelDef(project, level); err == nil {
+		size = def.Size
+	}
+	return
+}
+
+// RegisterLDtkEntityFactory register a ldtk.EntityFactory that will be call for each entity instance of a given
+// identifier when a ldtk.Level is added to the world
+func (e Engine) RegisterLDtkEntityFactory(identifier string, factory ldtk.EntityFactory) {
+	e.ldm.RegisterEntityFactory(identifier, factory)
+}
+
+// GetLDtkIntGrid returns the value, and its identifier, of a cell in an IntGrid layer of a ldtk.Level entity
+func (e Engine) GetLDtkIntGrid(levelID goecs.EntityID, layer string, col, row int) (int, string, error) {
+	return e.ldm.GetIntGrid(e.world, levelID, layer, col, row)
+}
+
+// GetLDtkIntGridAt returns the value, and its identifier, of the cell in a world geometry.Point in an IntGrid layer
+// of a ldtk.Level entity
+func (e Engine) GetLDtkIntGridAt(levelID goecs.EntityID, layer string, at geometry.Point) (value int, name string,
+	err error) {
+	var col, row int
+	if col, row, err = e.ldm.WorldToCell(e.world, levelID, layer, at); err == nil {
+		value, name, err = e.ldm.GetIntGrid(e.world, levelID, layer, col, row)
+	}
+	return
+}
+
 // GetSettings return the in game settings
 func (e Engine) GetSettings() options.Settings {
 	return &e.opt
@@ -347,7 +532,7 @@ func New(opt options.Options, init InitFunc) *Engine {
 	dm := managers.Device()
 	sm := managers.Storage(dm)
 	cm := managers.Collisions(sm)
-	return &Engine{
+	e := &Engine{
 		opt:    opt,
 		world:  goecs.Default(),
 		status: statusInitializing,
@@ -357,4 +542,8 @@ func New(opt options.Options, init InitFunc) *Engine {
 		dm:     dm,
 		stages: make(map[string]InitFunc),
 	}
+	e.lm = managers.Localization(sm, &e.opt)
+	e.tm = managers.TiledMaps(sm)
+	e.ldm = managers.LDtkLevels(sm)
+	return e
 }
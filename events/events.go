@@ -27,6 +27,7 @@ import (
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge/components/audio"
 	"github.com/juan-medina/gosge/components/device"
+	"github.com/juan-medina/gosge/components/effects"
 	"github.com/juan-medina/gosge/components/geometry"
 )
 
@@ -255,6 +256,140 @@ func (g GamePadStickMoveEvent) Type() goecs.ComponentType {
 	return TYPE.GamePadStickMoveEvent
 }
 
+// ChangeLanguageEvent is an event to change the current language, all ui.LocalizedText will be updated, languages
+// that are not loaded are ignored
+type ChangeLanguageEvent struct {
+	Language string // Language to change to, it should be loaded with engine.LoadLocale
+}
+
+// Type is this goecs.ComponentType
+func (c ChangeLanguageEvent) Type() goecs.ComponentType {
+	return TYPE.ChangeLanguageEvent
+}
+
+// TweenFinishedEvent is an event trigger when an effects.Tween finish
+type TweenFinishedEvent struct {
+	Entity goecs.EntityID // Entity is the entity that has the effects.Tween
+	Target goecs.EntityID // Target is the entity that was animated
+}
+
+// Type is this goecs.ComponentType
+func (t TweenFinishedEvent) Type() goecs.ComponentType {
+	return TYPE.TweenFinishedEvent
+}
+
+// AnimationFinishedEvent is an event trigger when an animation.Sequence reach its end, looping sequences trigger it
+// every time that they complete a cycle
+type AnimationFinishedEvent struct {
+	Entity   goecs.EntityID // Entity is the entity that has the animation.Animation
+	Sequence string         // Sequence is the name of the animation.Sequence that has finished
+	Frame    int32          // Frame is the frame number where the animation.Sequence has finished
+}
+
+// Type is this goecs.ComponentType
+func (a AnimationFinishedEvent) Type() goecs.ComponentType {
+	return TYPE.AnimationFinishedEvent
+}
+
+// AnimationFrameEvent is an event trigger when an animation.Sequence show a frame
+type AnimationFrameEvent struct {
+	Entity   goecs.EntityID // Entity is the entity that has the animation.Animation
+	Sequence string         // Sequence is the name of the animation.Sequence
+	Frame    int32          // Frame is the frame number that is show
+}
+
+// Type is this goecs.ComponentType
+func (a AnimationFrameEvent) Type() goecs.ComponentType {
+	return TYPE.AnimationFrameEvent
+}
+
+// AnimationStateChangeEvent is an event trigger when an animation.StateMachine change state
+type AnimationStateChangeEvent struct {
+	Entity goecs.EntityID // Entity is the entity that has the animation.StateMachine
+	From   string         // From is the previous state
+	To     string         // To is the new state
+}
+
+// Type is this goecs.ComponentType
+func (a AnimationStateChangeEvent) Type() goecs.ComponentType {
+	return TYPE.AnimationStateChangeEvent
+}
+
+// CutsceneFinishedEvent is an event trigger when a cutscene.Player finish its timeline
+type CutsceneFinishedEvent struct {
+	Entity goecs.EntityID // Entity is the entity that has the cutscene.Player
+}
+
+// Type is this goecs.ComponentType
+func (c CutsceneFinishedEvent) Type() goecs.ComponentType {
+	return TYPE.CutsceneFinishedEvent
+}
+
+// CutsceneSignalEvent is an event trigger by a cutscene signal action that has no signal, such ones loaded from JSON
+type CutsceneSignalEvent struct {
+	Entity goecs.EntityID // Entity is the entity that has the cutscene.Player
+	Name   string         // Name is the action name
+}
+
+// Type is this goecs.ComponentType
+func (c CutsceneSignalEvent) Type() goecs.ComponentType {
+	return TYPE.CutsceneSignalEvent
+}
+
+// ColorEffectFinishedEvent is an event trigger when an effects.Fade, effects.Flash, effects.Pulse or effects.Blink
+// finish
+type ColorEffectFinishedEvent struct {
+	Entity goecs.EntityID      // Entity is the entity that had the effect
+	Effect goecs.ComponentType // Effect is the goecs.ComponentType of the effect that has finished
+}
+
+// Type is this goecs.ComponentType
+func (c ColorEffectFinishedEvent) Type() goecs.ComponentType {
+	return TYPE.ColorEffectFinishedEvent
+}
+
+// ShakeEvent is an event that shakes the rendered world, each shake adds trauma that stacks with the trauma of
+// previous shakes, the shake strength is the square of the trauma, so small shakes are subtle and big ones are
+// violent. Entities in an effects.Layer that is Fixed will not shake
+type ShakeEvent struct {
+	Trauma    float32 // Trauma is how much trauma, from 0 to 1, this shake adds, the total trauma is clamped to 1
+	Intensity float32 // Intensity is the max offset, in pixels, that we move the world with a trauma of 1
+	Frequency float32 // Frequency is how many times per second the shake changes direction, if is 0 we use 15
+	Duration  float32 // Duration is the time, in seconds, that all the Trauma takes to go away if Decay is 0
+	Decay     float32 // Decay is how much trauma goes away each second, if is 0 we use the Duration
+}
+
+// Type is this goecs.ComponentType
+func (s ShakeEvent) Type() goecs.ComponentType {
+	return TYPE.ShakeEvent
+}
+
+// CameraKickEvent is an event that moves the rendered world an offset and return it back over time, kicks stacks
+// with each other. Entities in an effects.Layer that is Fixed will not move
+type CameraKickEvent struct {
+	Offset geometry.Point // Offset is how much we move the rendered world at the start of the kick
+	Time   float32        // Time is how long, in seconds, takes to return
+	Ease   effects.Easing // Ease is the effects.Easing for returning
+}
+
+// Type is this goecs.ComponentType
+func (c CameraKickEvent) Type() goecs.ComponentType {
+	return TYPE.CameraKickEvent
+}
+
+// ZoomPunchEvent is an event that zooms the rendered world, from the center of the screen, and return it back over
+// time, punches stacks with each other. Entities in an effects.Layer that is Fixed will not zoom
+type ZoomPunchEvent struct {
+	Amount float32        // Amount is how much we zoom at the start of the punch, ex: 0.1 zooms in a 10%
+	Time   float32        // Time is how long, in seconds, takes to return
+	Ease   effects.Easing // Ease is the effects.Easing for returning
+}
+
+// Type is this goecs.ComponentType
+func (z ZoomPunchEvent) Type() goecs.ComponentType {
+	return TYPE.ZoomPunchEvent
+}
+
 type types struct {
 	// GameCloseEvent is the goecs.ComponentType for events.GameCloseEvent
 	GameCloseEvent goecs.ComponentType
@@ -298,29 +433,62 @@ type types struct {
 	GamePadButtonDownEvent goecs.ComponentType
 	// GamePadStickMoveEvent is the goecs.ComponentType for events.GamePadStickMoveEvent
 	GamePadStickMoveEvent goecs.ComponentType
+	// ChangeLanguageEvent is the goecs.ComponentType for events.ChangeLanguageEvent
+	ChangeLanguageEvent goecs.ComponentType
+	// TweenFinishedEvent is the goecs.ComponentType for events.TweenFinishedEvent
+	TweenFinishedEvent goecs.ComponentType
+	// AnimationFinishedEvent is the goecs.ComponentType for events.AnimationFinishedEvent
+	AnimationFinishedEvent goecs.ComponentType
+	// AnimationFrameEvent is the goecs.ComponentType for events.AnimationFrameEvent
+	AnimationFrameEvent goecs.ComponentType
+	// AnimationStateChangeEvent is the goecs.ComponentType for events.AnimationStateChangeEvent
+	AnimationStateChangeEvent goecs.ComponentType
+	// CutsceneFinishedEvent is the goecs.ComponentType for events.CutsceneFinishedEvent
+	CutsceneFinishedEvent goecs.ComponentType
+	// CutsceneSignalEvent is the goecs.ComponentType for events.CutsceneSignalEvent
+	CutsceneSignalEvent goecs.ComponentType
+	// ColorEffectFinishedEvent is the goecs.ComponentType for events.ColorEffectFinishedEvent
+	ColorEffectFinishedEvent goecs.ComponentType
+	// ShakeEvent is the goecs.ComponentType for events.ShakeEvent
+	ShakeEvent goecs.ComponentType
+	// CameraKickEvent is the goecs.ComponentType for events.CameraKickEvent
+	CameraKickEvent goecs.ComponentType
+	// ZoomPunchEvent is the goecs.ComponentType for events.ZoomPunchEvent
+	ZoomPunchEvent goecs.ComponentType
 }
 
 // TYPE hold the goecs.ComponentType for our events
 var TYPE = types{
-	GameCloseEvent:          goecs.NewComponentType(),
-	ChangeGameStage:         goecs.NewComponentType(),
-	DelaySignal:             goecs.NewComponentType(),
-	PlaySoundEvent:          goecs.NewComponentType(),
-	ChangeMasterVolumeEvent: goecs.NewComponentType(),
-	PlayMusicEvent:          goecs.NewComponentType(),
-	StopMusicEvent:          goecs.NewComponentType(),
-	PauseMusicEvent:         goecs.NewComponentType(),
-	ResumeMusicEvent:        goecs.NewComponentType(),
-	ChangeMusicVolumeEvent:  goecs.NewComponentType(),
-	MouseMoveEvent:          goecs.NewComponentType(),
-	MouseDownEvent:          goecs.NewComponentType(),
-	MouseUpEvent:            goecs.NewComponentType(),
-	KeyDownEvent:            goecs.NewComponentType(),
-	KeyUpEvent:              goecs.NewComponentType(),
-	MusicStateChangeEvent:   goecs.NewComponentType(),
-	FocusOnControlEvent:     goecs.NewComponentType(),
-	ClearFocusEvent:         goecs.NewComponentType(),
-	GamePadButtonUpEvent:    goecs.NewComponentType(),
-	GamePadButtonDownEvent:  goecs.NewComponentType(),
-	GamePadStickMoveEvent:   goecs.NewComponentType(),
+	GameCloseEvent:            goecs.NewComponentType(),
+	ChangeGameStage:           goecs.NewComponentType(),
+	DelaySignal:               goecs.NewComponentType(),
+	PlaySoundEvent:            goecs.NewComponentType(),
+	ChangeMasterVolumeEvent:   goecs.NewComponentType(),
+	PlayMusicEvent:            goecs.NewComponentType(),
+	StopMusicEvent:            goecs.NewComponentType(),
+	PauseMusicEvent:           goecs.NewComponentType(),
+	ResumeMusicEvent:          goecs.NewComponentType(),
+	ChangeMusicVolumeEvent:    goecs.NewComponentType(),
+	MouseMoveEvent:            goecs.NewComponentType(),
+	MouseDownEvent:            goecs.NewComponentType(),
+	MouseUpEvent:              goecs.NewComponentType(),
+	KeyDownEvent:              goecs.NewComponentType(),
+	KeyUpEvent:                goecs.NewComponentType(),
+	MusicStateChangeEvent:     goecs.NewComponentType(),
+	FocusOnControlEvent:       goecs.NewComponentType(),
+	ClearFocusEvent:           goecs.NewComponentType(),
+	GamePadButtonUpEvent:      goecs.NewComponentType(),
+	GamePadButtonDownEvent:    goecs.NewComponentType(),
+	GamePadStickMoveEvent:     goecs.NewComponentType(),
+	ChangeLanguageEvent:       goecs.NewComponentType(),
+	TweenFinishedEvent:        goecs.NewComponentType(),
+	AnimationFinishedEvent:    goecs.NewComponentType(),
+	AnimationFrameEvent:       goecs.NewComponentType(),
+	AnimationStateChangeEvent: goecs.NewComponentType(),
+	CutsceneFinishedEvent:     goecs.NewComponentType(),
+	CutsceneSignalEvent:       goecs.NewComponentType(),
+	ColorEffectFinishedEvent:  goecs.NewComponentType(),
+	ShakeEvent:                goecs.NewComponentType(),
+	CameraKickEvent:           goecs.NewComponentType(),
+	ZoomPunchEvent:            goecs.NewComponentType(),
 }
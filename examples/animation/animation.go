@@ -112,16 +112,7 @@ func loadGame(eng *gosge.Engine) error {
 	for l := int32(1); l <= numLayers; l++ {
 		// generate the layer file name
 		layerFile := fmt.Sprintf(layerFile, l)
-		// add a sprite that will be attached to the sprite that we scroll
-		attached := world.AddEntity(
-			sprite.Sprite{
-				Name:  layerFile,
-				Scale: gameScale.Max,
-			},
-			geometry.Point{X: -9000}, // we move it out of the screen
-			effects.Layer{Depth: float32(l)},
-		)
-		// add each layer to the screen
+		// add each layer to the screen, repeating horizontally
 		world.AddEntity(
 			sprite.Sprite{
 				Name:  layerFile,
@@ -129,7 +120,7 @@ func loadGame(eng *gosge.Engine) error {
 			},
 			geometry.Point{},
 			effects.Layer{Depth: float32(l)}, // each layer is have it own depth
-			attach{id: attached},             // attach the layer that is attached
+			effects.Parallax{WrapX: true},    // the layer scroll with the velocity that we set when running
 		)
 	}
 
@@ -195,69 +186,37 @@ func loadGame(eng *gosge.Engine) error {
 	return nil
 }
 
-// a component to have an attached entity
-type attach struct {
-	id goecs.EntityID
-}
-
-func (a attach) Type() goecs.ComponentType {
-	return attachType
-}
-
-// the goecs.ComponentType of the attach struct
-var attachType = goecs.NewComponentType()
-
 // on update
-func robotMoveSystem(world *goecs.World, delta float32) error {
+func robotMoveSystem(world *goecs.World, _ float32) error {
 	// get the robot id
 	robotEnt := world.Get(robot)
 	// get the animation from our robot
 	anim := animation.Get.Animation(robotEnt)
-	// if its running
-	if anim.Current == runAnim {
-		// get from the ECS all the entities that have a layer a position and an attachment
-		for it := world.Iterator(effects.TYPE.Layer, geometry.TYPE.Point, attachType); it != nil; it = it.Next() {
-			// get the entity
-			ent := it.Value()
 
-			/// get the layer and the position
-			ly := effects.Get.Layer(ent)
-			pos := geometry.Get.Point(ent)
-
-			// the direction that we are moving in 1/-1 format
-			direction := float32(1)
-			if anim.FlipX {
-				direction = -1
-			}
-
-			// what is the width of this layer is
-			layerWidth := designResolution.Width * gameScale.Max
+	// the direction that we are moving in 1/-1 format
+	direction := float32(1)
+	if anim.FlipX {
+		direction = -1
+	}
 
-			// moving according the direction we are facing
-			pos.X -= delta * moveSpeed * (8 - ly.Depth) * direction * gameScale.Max
+	// get from the ECS all the entities that have a layer and a parallax
+	for it := world.Iterator(effects.TYPE.Layer, effects.TYPE.Parallax); it != nil; it = it.Next() {
+		// get the entity
+		ent := it.Value()
 
-			// check if we need to wrap since we are completely off-screen
-			if pos.X < -layerWidth {
-				pos.X = 0
-			} else if pos.X > layerWidth {
-				pos.X = 0
-			}
+		/// get the layer and the parallax
+		ly := effects.Get.Layer(ent)
+		parallax := effects.Get.Parallax(ent)
 
-			// this will be attachment pos
-			attachPos := geometry.Point{}
-
-			// if the layer is moving to the right
-			if pos.X > 0 {
-				attachPos.X = pos.X - layerWidth // attach to the left
-			} else {
-				attachPos.X = pos.X + layerWidth // attach to the right
-			}
-			// get the robot id
-			id := ent.Get(attachType).(attach).id
-			world.Get(id).Set(attachPos)
-			// update layer pos
-			ent.Set(pos)
+		// if its running move according the direction we are facing, far layers move slower
+		if anim.Current == runAnim {
+			parallax.Velocity.X = -moveSpeed * (numLayers - ly.Depth) * direction * gameScale.Max
+		} else {
+			parallax.Velocity.X = 0
 		}
+
+		// update layer parallax
+		ent.Set(parallax)
 	}
 	return nil
 }
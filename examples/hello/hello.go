@@ -23,11 +23,14 @@
 package main
 
 import (
+	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge"
 	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/device"
 	"github.com/juan-medina/gosge/components/effects"
 	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
 	"github.com/juan-medina/gosge/options"
 	"github.com/rs/zerolog/log"
 )
@@ -46,11 +49,20 @@ const (
 	fontName  = "resources/go_regular.fnt"
 	fontBig   = 100
 	fontSmall = 60
+	fontTiny  = 40
 )
 
 var (
 	// designResolution is how our game is designed
 	designResolution = geometry.Size{Width: 1920, Height: 1080}
+	// languages that we could change to
+	languages = []string{"en", "es"}
+	// locales to load
+	locales = []string{"resources/locale/en.json", "resources/locale/es.po"}
+	// counterEnt is the entity for the text that count language changes
+	counterEnt *goecs.Entity
+	// gEng is the game engine
+	gEng *gosge.Engine
 )
 
 func main() {
@@ -61,11 +73,20 @@ func main() {
 
 func loadGame(eng *gosge.Engine) error {
 
+	gEng = eng
+
 	// Preload font
 	if err := eng.LoadFont(fontName); err != nil {
 		return err
 	}
 
+	// Preload locales
+	for _, locale := range locales {
+		if err := eng.LoadLocale(locale); err != nil {
+			return err
+		}
+	}
+
 	world := eng.World()
 
 	// gameScale has a geometry.Scale from the real screen size to our designResolution
@@ -73,8 +94,8 @@ func loadGame(eng *gosge.Engine) error {
 
 	// add the centered text
 	world.AddEntity(
+		ui.LocalizedText{Key: "hello"},
 		ui.Text{
-			String:     "Hello World",
 			HAlignment: ui.CenterHAlignment,
 			VAlignment: ui.MiddleVAlignment,
 			Font:       fontName,
@@ -90,12 +111,21 @@ func loadGame(eng *gosge.Engine) error {
 			From:  color.Red,
 			To:    color.Yellow,
 		},
+		ui.TextStyle{
+			Outline:          color.DarkBlue,
+			OutlineThickness: 4 * gameScale.Max,
+			Shadow:           geometry.Size{Width: 10 * gameScale.Max, Height: 10 * gameScale.Max},
+			ShadowColor:      color.Black.Alpha(127),
+			Animation:        ui.WaveTextAnimation,
+			Amplitude:        15 * gameScale.Max,
+			Speed:            0.5,
+		},
 	)
 
 	// add the bottom text
 	world.AddEntity(
+		ui.LocalizedText{Key: "press_to_close"},
 		ui.Text{
-			String:     "press <ESC> to close",
 			HAlignment: ui.CenterHAlignment,
 			VAlignment: ui.BottomVAlignment,
 			Font:       fontName,
@@ -111,5 +141,47 @@ func loadGame(eng *gosge.Engine) error {
 			To:   color.White.Alpha(0),
 		},
 	)
+
+	// add the language changes counter
+	counterEnt = world.Get(world.AddEntity(
+		ui.LocalizedText{Key: "times_changed"},
+		ui.Text{
+			HAlignment: ui.CenterHAlignment,
+			VAlignment: ui.TopVAlignment,
+			Font:       fontName,
+			Size:       fontTiny * gameScale.Max,
+		},
+		geometry.Point{
+			X: designResolution.Width / 2 * gameScale.Point.X,
+		},
+		color.White,
+	))
+
+	// listen to keys
+	world.AddListener(keyEvents, events.TYPE.KeyUpEvent)
+
+	return nil
+}
+
+func keyEvents(world *goecs.World, signal goecs.Component, _ float32) error {
+	switch e := signal.(type) {
+	case events.KeyUpEvent:
+		if e.Key == device.KeySpace {
+			// change to the next language
+			current := gEng.GetLanguage()
+			next := languages[0]
+			for i, language := range languages {
+				if language == current {
+					next = languages[(i+1)%len(languages)]
+				}
+			}
+			world.Signal(events.ChangeLanguageEvent{Language: next})
+
+			// count the changes, the text will be resolved again with the new count
+			lt := ui.Get.LocalizedText(counterEnt)
+			lt.Count++
+			counterEnt.Set(lt)
+		}
+	}
 	return nil
 }
@@ -26,12 +26,18 @@ import (
 	"fmt"
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge/components/animation"
+	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/juan-medina/gosge/components/sprite"
+	"github.com/juan-medina/gosge/events"
 )
 
 type animationManager struct{}
 
-func (animationManager) System(world *goecs.World, delta float32) error {
+func (am animationManager) System(world *goecs.World, delta float32) error {
+	if err := am.stateMachineSystem(world, delta); err != nil {
+		return err
+	}
+
 	for it := world.Iterator(animation.TYPE.Animation); it != nil; it = it.Next() {
 		ent := it.Value()
 
@@ -46,12 +52,24 @@ func (animationManager) System(world *goecs.World, delta float32) error {
 		}
 
 		if state.Current != anim.Current {
-			state = animation.State{}
+			// keep the offset that the previous sequence has applied, so it gets removed
+			state = animation.State{Offset: state.Offset}
 			state.Current = anim.Current
 			if _, ok := anim.Sequences[anim.Current]; !ok {
 				return fmt.Errorf("can not find animation: %q", anim.Current)
 			}
 			seq := anim.Sequences[anim.Current]
+			if len(seq.FrameList) == 0 {
+				seq = resolveFrames(seq)
+			}
+			seq.Frames = int32(len(seq.FrameList))
+			if seq.Frames == 0 {
+				return fmt.Errorf("animation %q has no frames", anim.Current)
+			}
+			if seq.Mode == animation.Reverse || seq.Mode == animation.ReverseOnce {
+				state.Frame = seq.Frames - 1
+			}
+			world.Signal(events.AnimationFrameEvent{Entity: ent.ID(), Sequence: state.Current, Frame: state.Frame})
 			ent.Set(seq)
 			ent.Set(state)
 			ent.Set(anim)
@@ -70,22 +88,47 @@ func (animationManager) System(world *goecs.World, delta float32) error {
 		anim := animation.Get.Animation(ent)
 		state := animation.Get.State(ent)
 
-		if state.Time += delta * state.Speed; state.Time > seq.Delay {
+		frame := seq.FrameList[state.Frame]
+		delay := frame.Duration
+		if delay == 0 {
+			delay = seq.Delay
+		}
+
+		if state.Time += delta * state.Speed; state.Time > delay && !state.Ended {
 			state.Time = 0
-			if state.Frame++; state.Frame >= seq.Frames {
-				state.Frame = 0
+			previous := state.Frame
+			if advanceFrame(seq, &state) {
+				world.Signal(events.AnimationFinishedEvent{Entity: ent.ID(), Sequence: state.Current, Frame: state.Frame})
+				if state.Ended && seq.Next != "" {
+					anim.Current = seq.Next
+				}
+			}
+			if state.Frame != previous {
+				world.Signal(events.AnimationFrameEvent{Entity: ent.ID(), Sequence: state.Current, Frame: state.Frame})
+				frame = seq.FrameList[state.Frame]
 			}
 		}
 
-		spriteName := fmt.Sprintf(seq.Base, state.Frame+1)
+		// move the entity with the frame offset
+		offset := geometry.Point{X: frame.Offset.X * seq.Scale, Y: frame.Offset.Y * seq.Scale}
+		if anim.FlipX {
+			offset.X = -offset.X
+		}
+		if anim.FlipY {
+			offset.Y = -offset.Y
+		}
+		if offset != state.Offset && ent.Contains(geometry.TYPE.Point) {
+			ent.Set(geometry.Get.Point(ent).Add(offset.Sub(state.Offset)))
+			state.Offset = offset
+		}
 
 		spr := sprite.Sprite{
 			Sheet:    seq.Sheet,
-			Name:     spriteName,
+			Name:     frame.Name,
 			Scale:    seq.Scale,
 			Rotation: seq.Rotation,
-			FlipX:    anim.FlipX,
-			FlipY:    anim.FlipY,
+			FlipX:    anim.FlipX != frame.FlipX,
+			FlipY:    anim.FlipY != frame.FlipY,
 		}
 
 		ent.Set(spr)
@@ -97,6 +140,157 @@ func (animationManager) System(world *goecs.World, delta float32) error {
 	return nil
 }
 
+// stateMachineSystem change the current sequence of the animations that have an animation.StateMachine
+func (am animationManager) stateMachineSystem(world *goecs.World, delta float32) error {
+	for it := world.Iterator(animation.TYPE.StateMachine, animation.TYPE.Animation); it != nil; it = it.Next() {
+		ent := it.Value()
+		machine := animation.Get.StateMachine(ent)
+		anim := animation.Get.Animation(ent)
+
+		// init the state or get it from entity
+		var state animation.MachineState
+		if ent.Contains(animation.TYPE.MachineState) {
+			state = animation.Get.MachineState(ent)
+			state.Time += delta * anim.Speed
+		} else {
+			state = animation.MachineState{Current: machine.Initial}
+		}
+
+		for _, tr := range machine.Transitions {
+			if tr.From != state.Current && (tr.From != animation.AnyState || tr.To == state.Current) {
+				continue
+			}
+			if tr.ExitTime > 0 && sequenceTime(anim.Sequences[machine.States[state.Current]])*tr.ExitTime > state.Time {
+				continue
+			}
+			if !conditionsMet(machine, tr.Conditions) {
+				continue
+			}
+			// triggers are used when the transition happen
+			for _, cond := range tr.Conditions {
+				if cond.Op == animation.Triggered {
+					delete(machine.Triggers, cond.Param)
+				}
+			}
+			world.Signal(events.AnimationStateChangeEvent{Entity: ent.ID(), From: state.Current, To: tr.To})
+			state = animation.MachineState{Current: tr.To}
+			break
+		}
+
+		seq, ok := machine.States[state.Current]
+		if !ok {
+			return fmt.Errorf("can not find animation state: %q", state.Current)
+		}
+		if anim.Current != seq {
+			anim.Current = seq
+			ent.Set(anim)
+		}
+		ent.Set(state)
+	}
+	return nil
+}
+
+// conditionsMet returns if all the animation.Condition are true for the parameters of an animation.StateMachine
+func conditionsMet(machine animation.StateMachine, conditions []animation.Condition) bool {
+	for _, cond := range conditions {
+		value := machine.Floats[cond.Param]
+		met := false
+		switch cond.Op {
+		case animation.Greater:
+			met = value > cond.Value
+		case animation.Less:
+			met = value < cond.Value
+		case animation.Equals:
+			met = value == cond.Value
+		case animation.NotEquals:
+			met = value != cond.Value
+		case animation.IsTrue:
+			met = machine.Bools[cond.Param]
+		case animation.IsFalse:
+			met = !machine.Bools[cond.Param]
+		case animation.Triggered:
+			met = machine.Triggers[cond.Param]
+		}
+		if !met {
+			return false
+		}
+	}
+	return true
+}
+
+// sequenceTime returns how long takes to play all the frames of an animation.Sequence
+func sequenceTime(seq animation.Sequence) (total float32) {
+	if len(seq.FrameList) == 0 {
+		return float32(seq.Frames) * seq.Delay
+	}
+	for _, frame := range seq.FrameList {
+		if frame.Duration == 0 {
+			total += seq.Delay
+		} else {
+			total += frame.Duration
+		}
+	}
+	return
+}
+
+// resolveFrames returns an animation.Sequence with its animation.Frame list build from its Base name
+func resolveFrames(seq animation.Sequence) animation.Sequence {
+	seq.FrameList = make([]animation.Frame, seq.Frames)
+	for i := range seq.FrameList {
+		seq.FrameList[i] = animation.Frame{Name: fmt.Sprintf(seq.Base, i+1)}
+	}
+	return seq
+}
+
+// advanceFrame moves the animation.State to the next frame of an animation.Sequence following its play mode, and
+// returns if the sequence has reach its end
+func advanceFrame(seq animation.Sequence, state *animation.State) (finished bool) {
+	last := seq.Frames - 1
+	switch seq.Mode {
+	case animation.Once:
+		if state.Frame >= last {
+			state.Ended = true
+			return true
+		}
+		state.Frame++
+	case animation.ReverseOnce:
+		if state.Frame <= 0 {
+			state.Ended = true
+			return true
+		}
+		state.Frame--
+	case animation.Reverse:
+		if state.Frame--; state.Frame < 0 {
+			state.Frame = last
+			return true
+		}
+	case animation.PingPong:
+		if last <= 0 {
+			return true
+		}
+		if state.Back {
+			if state.Frame <= 0 {
+				// with two frames the second one is already the last
+				state.Frame = 1
+				state.Back = state.Frame >= last
+				return true
+			}
+			state.Frame--
+		} else {
+			if state.Frame++; state.Frame >= last {
+				state.Frame = last
+				state.Back = true
+			}
+		}
+	default:
+		if state.Frame++; state.Frame >= seq.Frames {
+			state.Frame = 0
+			return true
+		}
+	}
+	return false
+}
+
 // Animation returns a managers.WithSystem for handling animations
 func Animation() WithSystem {
 	return &animationManager{}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"github.com/juan-medina/gosge/components/animation"
+	"testing"
+)
+
+func TestAdvanceFrame(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     animation.PlayMode
+		frames   int32
+		start    int32
+		want     []int32
+		finished []bool
+	}{
+		{"loop one frame", animation.Loop, 1, 0, []int32{0, 0}, []bool{true, true}},
+		{"loop two frames", animation.Loop, 2, 0, []int32{1, 0, 1, 0}, []bool{false, true, false, true}},
+		{"loop three frames", animation.Loop, 3, 0, []int32{1, 2, 0, 1}, []bool{false, false, true, false}},
+		{"once one frame", animation.Once, 1, 0, []int32{0, 0}, []bool{true, true}},
+		{"once two frames", animation.Once, 2, 0, []int32{1, 1, 1}, []bool{false, true, true}},
+		{"once three frames", animation.Once, 3, 0, []int32{1, 2, 2}, []bool{false, false, true}},
+		{"reverse one frame", animation.Reverse, 1, 0, []int32{0, 0}, []bool{true, true}},
+		{"reverse two frames", animation.Reverse, 2, 1, []int32{0, 1, 0, 1}, []bool{false, true, false, true}},
+		{"reverse three frames", animation.Reverse, 3, 2, []int32{1, 0, 2, 1}, []bool{false, false, true, false}},
+		{"reverse once one frame", animation.ReverseOnce, 1, 0, []int32{0, 0}, []bool{true, true}},
+		{"reverse once two frames", animation.ReverseOnce, 2, 1, []int32{0, 0, 0}, []bool{false, true, true}},
+		{"reverse once three frames", animation.ReverseOnce, 3, 2, []int32{1, 0, 0}, []bool{false, false, true}},
+		{"ping pong one frame", animation.PingPong, 1, 0, []int32{0, 0}, []bool{true, true}},
+		{"ping pong two frames", animation.PingPong, 2, 0, []int32{1, 0, 1, 0, 1},
+			[]bool{false, false, true, false, true}},
+		{"ping pong three frames", animation.PingPong, 3, 0, []int32{1, 2, 1, 0, 1, 2, 1, 0, 1},
+			[]bool{false, false, false, false, true, false, false, false, true}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			seq := animation.Sequence{Mode: tc.mode, Frames: tc.frames}
+			state := animation.State{Frame: tc.start}
+			for i := range tc.want {
+				finished := advanceFrame(seq, &state)
+				if state.Frame != tc.want[i] || finished != tc.finished[i] {
+					t.Fatalf("step %d got frame %d finished %v, want frame %d finished %v", i, state.Frame, finished,
+						tc.want[i], tc.finished[i])
+				}
+			}
+		})
+	}
+}
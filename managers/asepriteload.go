@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/animation"
+	"github.com/juan-medina/gosge/components/geometry"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	asepriteApp             = "aseprite"         // asepriteApp is how Aseprite identify itself in the JSON meta
+	asepriteReverse         = "reverse"          // asepriteReverse is a tag that plays from last to first frame
+	asepritePingPong        = "pingpong"         // asepritePingPong is a tag that plays forward and then back
+	asepritePingPongReverse = "pingpong_reverse" // asepritePingPongReverse is a tag that plays back and then forward
+)
+
+// asepriteRect is a rectangle in an Aseprite JSON
+type asepriteRect struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+	W float32 `json:"w"`
+	H float32 `json:"h"`
+}
+
+// asepriteFrame is a frame in an Aseprite JSON, its name is only set in the array variant
+type asepriteFrame struct {
+	Name             string       `json:"filename"`
+	Frame            asepriteRect `json:"frame"`
+	Trimmed          bool         `json:"trimmed"`
+	SpriteSourceSize asepriteRect `json:"spriteSourceSize"`
+	SourceSize       asepriteRect `json:"sourceSize"`
+	Duration         float32      `json:"duration"`
+}
+
+// asepriteData is the Aseprite JSON, frames could be an array or a hash
+type asepriteData struct {
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		App       string `json:"app"`
+		Image     string `json:"image"`
+		FrameTags []struct {
+			Name      string `json:"name"`
+			From      int    `json:"from"`
+			To        int    `json:"to"`
+			Direction string `json:"direction"`
+			Repeat    string `json:"repeat"`
+		} `json:"frameTags"`
+	} `json:"meta"`
+}
+
+// isAsepriteSheet returns if a sprite sheet JSON has been exported from Aseprite, other tools like TexturePacker
+// could export the frames as a hash too, so we only use the app that exported it
+func isAsepriteSheet(content []byte) bool {
+	data := asepriteData{}
+	if err := json.Unmarshal(content, &data); err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(data.Meta.App), asepriteApp)
+}
+
+// asepriteFrames returns the frames in an Aseprite JSON in order, for the hash variant the name is the key
+func asepriteFrames(raw json.RawMessage) (frames []asepriteFrame, err error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		err = json.Unmarshal(raw, &frames)
+		return
+	}
+
+	// a map will lose the order of the frames, that the tags refer to, so we decode the hash one by one
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err = dec.Token(); err != nil {
+		return
+	}
+	for dec.More() {
+		var token json.Token
+		if token, err = dec.Token(); err != nil {
+			return
+		}
+		frame := asepriteFrame{}
+		if err = dec.Decode(&frame); err != nil {
+			return
+		}
+		frame.Name = fmt.Sprint(token)
+		frames = append(frames, frame)
+	}
+	return
+}
+
+// handleAsepriteSheet adds a sprite sheet from an Aseprite JSON, each frame tag will become an animation.Sequence
+func (sm *StorageManager) handleAsepriteSheet(content []byte, name string) (err error) {
+	data := asepriteData{}
+	if err = json.Unmarshal(content, &data); err != nil {
+		return
+	}
+
+	var frames []asepriteFrame
+	if frames, err = asepriteFrames(data.Frames); err != nil {
+		return
+	}
+	// validate the tags before adding anything, so a bad sheet is not half added
+	for _, tag := range data.Meta.FrameTags {
+		if tag.From < 0 || tag.To >= len(frames) || tag.From > tag.To {
+			return fmt.Errorf("invalid frames for tag %q in sprite sheet %q", tag.Name, name)
+		}
+	}
+
+	var texture components.TextureDef
+	texturePath := path.Join(filepath.Dir(name), data.Meta.Image)
+	if texture, err = sm.dm.LoadTexture(texturePath); err != nil {
+		return
+	}
+
+	st := make(spriteSheet, 0)
+	sm.sheets[name] = st
+	for _, frame := range frames {
+		// pivot in the center of the untrimmed frame, so trimmed frames stay in place
+		pivot := geometry.Point{X: 0.5, Y: 0.5}
+		if frame.Trimmed && frame.Frame.W > 0 && frame.Frame.H > 0 {
+			pivot = geometry.Point{
+				X: ((frame.SourceSize.W / 2) - frame.SpriteSourceSize.X) / frame.Frame.W,
+				Y: ((frame.SourceSize.H / 2) - frame.SpriteSourceSize.Y) / frame.Frame.H,
+			}
+		}
+		st[frame.Name] = components.SpriteDef{
+			Texture: texture,
+			Origin: geometry.Rect{
+				From: geometry.Point{
+					X: frame.Frame.X,
+					Y: frame.Frame.Y,
+				},
+				Size: geometry.Size{
+					Width:  frame.Frame.W,
+					Height: frame.Frame.H,
+				},
+			},
+			Pivot: pivot,
+		}
+	}
+
+	sequences := make(map[string]animation.Sequence)
+	for _, tag := range data.Meta.FrameTags {
+		seq := animation.Sequence{
+			Sheet: name,
+			Scale: 1,
+			Mode:  animation.Loop,
+		}
+		for _, frame := range frames[tag.From : tag.To+1] {
+			seq.FrameList = append(seq.FrameList, animation.Frame{
+				Name:     frame.Name,
+				Duration: frame.Duration / 1000,
+			})
+		}
+		once := tag.Repeat == "1"
+		switch tag.Direction {
+		case asepriteReverse:
+			seq.Mode = animation.Reverse
+			if once {
+				seq.Mode = animation.ReverseOnce
+			}
+		case asepritePingPong:
+			seq.Mode = animation.PingPong
+		case asepritePingPongReverse:
+			seq.Mode = animation.PingPong
+			for i, j := 0, len(seq.FrameList)-1; i < j; i, j = i+1, j-1 {
+				seq.FrameList[i], seq.FrameList[j] = seq.FrameList[j], seq.FrameList[i]
+			}
+		default:
+			if once {
+				seq.Mode = animation.Once
+			}
+		}
+		seq.Frames = int32(len(seq.FrameList))
+		seq.Delay = seq.FrameList[0].Duration
+		sequences[tag.Name] = seq
+	}
+	sm.sequences[name] = sequences
+
+	return
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/events"
+	"math"
+)
+
+const (
+	defaultShakeFrequency = 15 // defaultShakeFrequency is the shake frequency used when a events.ShakeEvent has none
+)
+
+// cameraPunch is a events.CameraKickEvent or events.ZoomPunchEvent in progress
+type cameraPunch struct {
+	offset  geometry.Point // offset is the starting offset for a kick
+	amount  float32        // amount is the starting zoom amount for a zoom punch
+	time    float32        // time is how long takes to return
+	ease    effects.Easing // ease is the effects.Easing for returning
+	elapsed float32        // elapsed is the time since the punch start
+}
+
+// remaining returns how much of the punch is still applied, from 1 to 0
+func (cp cameraPunch) remaining() float32 {
+	return 1 - cp.ease.Apply(effectProgress(cp.elapsed, cp.time))
+}
+
+type cameraManager struct {
+	trauma    float32        // trauma is the current shake trauma, from 0 to 1
+	intensity float32        // intensity is the max shake offset with a trauma of 1
+	frequency float32        // frequency is how many times per second the shake changes direction
+	decay     float32        // decay is how much trauma goes away each second
+	time      float32        // time is the running time of the shake, used for sampling the noise
+	kicks     []cameraPunch  // kicks are the events.CameraKickEvent in progress
+	punches   []cameraPunch  // punches are the events.ZoomPunchEvent in progress
+	offset    geometry.Point // offset is the current offset for the rendered world
+	zoom      float32        // zoom is the current zoom for the rendered world
+}
+
+// smoothNoise returns a pseudo random value between -1 and 1 that change smoothly for a given time and seed
+func smoothNoise(time float64, seed float64) float32 {
+	step := math.Floor(time)
+	from := shakeNoise(step + seed)
+	to := shakeNoise(step + 1 + seed)
+	t := float32(time - step)
+	t = t * t * (3 - (2 * t))
+	return from + ((to - from) * t)
+}
+
+func (cm *cameraManager) System(_ *goecs.World, delta float32) error {
+	cm.offset = geometry.Point{}
+	cm.zoom = 1
+
+	if cm.trauma > 0 {
+		cm.time += delta
+		shake := cm.intensity * cm.trauma * cm.trauma
+		cm.offset.X = smoothNoise(float64(cm.time*cm.frequency), 0) * shake
+		cm.offset.Y = smoothNoise(float64(cm.time*cm.frequency), 100.5) * shake
+		if cm.trauma -= cm.decay * delta; cm.trauma <= 0 {
+			cm.trauma = 0
+			cm.time = 0
+		}
+	}
+
+	kicks := cm.kicks[:0]
+	for _, kick := range cm.kicks {
+		kick.elapsed += delta
+		remaining := kick.remaining()
+		cm.offset.X += kick.offset.X * remaining
+		cm.offset.Y += kick.offset.Y * remaining
+		if kick.elapsed < kick.time {
+			kicks = append(kicks, kick)
+		}
+	}
+	cm.kicks = kicks
+
+	punches := cm.punches[:0]
+	for _, punch := range cm.punches {
+		punch.elapsed += delta
+		cm.zoom += punch.amount * punch.remaining()
+		if punch.elapsed < punch.time {
+			punches = append(punches, punch)
+		}
+	}
+	cm.punches = punches
+
+	return nil
+}
+
+func (cm *cameraManager) Listener(_ *goecs.World, signal goecs.Component, _ float32) error {
+	switch e := signal.(type) {
+	case events.ShakeEvent:
+		// if we are still shaking keep the strongest intensity
+		if cm.trauma <= 0 || e.Intensity > cm.intensity {
+			cm.intensity = e.Intensity
+		}
+		cm.frequency = e.Frequency
+		if cm.frequency <= 0 {
+			cm.frequency = defaultShakeFrequency
+		}
+		cm.trauma = float32(math.Min(float64(cm.trauma+e.Trauma), 1))
+		cm.decay = e.Decay
+		if cm.decay <= 0 {
+			if e.Duration > 0 {
+				// use the total trauma so stacked shakes still stop after the duration
+				cm.decay = cm.trauma / e.Duration
+			} else {
+				// a shake without decay or duration will lose all the trauma in a second
+				cm.decay = 1
+			}
+		}
+	case events.CameraKickEvent:
+		cm.kicks = append(cm.kicks, cameraPunch{offset: e.Offset, time: e.Time, ease: e.Ease})
+	case events.ZoomPunchEvent:
+		cm.punches = append(cm.punches, cameraPunch{amount: e.Amount, time: e.Time, ease: e.Ease})
+	}
+	return nil
+}
+
+func (cm *cameraManager) Signals() []goecs.ComponentType {
+	return []goecs.ComponentType{
+		events.TYPE.ShakeEvent,
+		events.TYPE.CameraKickEvent,
+		events.TYPE.ZoomPunchEvent,
+	}
+}
+
+func (cm *cameraManager) View() (offset geometry.Point, zoom float32) {
+	return cm.offset, cm.zoom
+}
+
+// CameraEffecter is a manager that handle the camera effects, like screen shake, kicks and zoom punches
+type CameraEffecter interface {
+	WithSystemAndListener
+	// View returns the offset and zoom that camera effects apply to the rendered world
+	View() (offset geometry.Point, zoom float32)
+}
+
+// CameraEffects returns a managers.CameraEffecter that handle camera effects
+func CameraEffects() CameraEffecter {
+	return &cameraManager{
+		kicks:   make([]cameraPunch, 0),
+		punches: make([]cameraPunch, 0),
+		zoom:    1,
+	}
+}
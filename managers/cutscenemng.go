@@ -0,0 +1,296 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/animation"
+	"github.com/juan-medina/gosge/components/cutscene"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/skeleton"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"sort"
+)
+
+type cutsceneManager struct {
+	input bool // input indicates that a key, mouse or gamepad button has been down since the last frame
+}
+
+func (cm *cutsceneManager) Signals() []goecs.ComponentType {
+	return []goecs.ComponentType{
+		events.TYPE.KeyDownEvent,
+		events.TYPE.MouseDownEvent,
+		events.TYPE.GamePadButtonDownEvent,
+	}
+}
+
+func (cm *cutsceneManager) Listener(_ *goecs.World, _ goecs.Component, _ float32) error {
+	cm.input = true
+	return nil
+}
+
+func (cm *cutsceneManager) System(world *goecs.World, delta float32) error {
+	input := cm.input
+	cm.input = false
+
+	var actors map[string]*goecs.Entity
+	finished := make([]*goecs.Entity, 0)
+
+	for it := world.Iterator(cutscene.TYPE.Player); it != nil; it = it.Next() {
+		ent := it.Value()
+		player := cutscene.Get.Player(ent)
+		tl := player.Timeline
+
+		if actors == nil {
+			actors = cm.actors(world)
+		}
+
+		// init the state or get it from entity
+		var state cutscene.State
+		if ent.Contains(cutscene.TYPE.State) {
+			state = cutscene.Get.State(ent)
+		} else {
+			state = cutscene.State{Actions: make([][]cutscene.ActionState, len(tl.Tracks))}
+			for i, track := range tl.Tracks {
+				state.Actions[i] = make([]cutscene.ActionState, len(track.Actions))
+			}
+		}
+
+		if player.Skip {
+			cm.run(world, ent.ID(), actors, tl, &state, true)
+		} else {
+			if state.Waiting && input {
+				cm.endWaits(tl, &state)
+			}
+			if !player.Paused && !state.Waiting {
+				speed := player.Speed
+				if speed == 0 {
+					speed = 1
+				}
+				state.Time = cm.nextStop(tl, state, state.Time+(delta*speed))
+				cm.run(world, ent.ID(), actors, tl, &state, false)
+			}
+		}
+
+		ent.Set(state)
+		if cm.done(state) {
+			finished = append(finished, ent)
+		}
+	}
+
+	for _, ent := range finished {
+		world.Signal(events.CutsceneFinishedEvent{Entity: ent.ID()})
+		ent.Remove(cutscene.TYPE.Player)
+		ent.Remove(cutscene.TYPE.State)
+		if ent.IsEmpty() {
+			if err := world.Remove(ent.ID()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// actors returns the entities that have a cutscene.Actor by name
+func (cm *cutsceneManager) actors(world *goecs.World) map[string]*goecs.Entity {
+	actors := make(map[string]*goecs.Entity)
+	for it := world.Iterator(cutscene.TYPE.Actor); it != nil; it = it.Next() {
+		ent := it.Value()
+		actors[cutscene.Get.Actor(ent).Name] = ent
+	}
+	return actors
+}
+
+// nextStop returns the time to move a cutscene.Timeline to, stopping in the first wait for input that is not ended
+func (cm *cutsceneManager) nextStop(tl cutscene.Timeline, state cutscene.State, time float32) float32 {
+	for ti, track := range tl.Tracks {
+		for ai, action := range track.Actions {
+			if action.Kind == cutscene.WaitInput && !state.Actions[ti][ai].Ended && action.Time < time {
+				time = action.Time
+			}
+		}
+	}
+	return time
+}
+
+// endWaits ends the wait for input actions that are running
+func (cm *cutsceneManager) endWaits(tl cutscene.Timeline, state *cutscene.State) {
+	for ti, track := range tl.Tracks {
+		for ai, action := range track.Actions {
+			if as := &state.Actions[ti][ai]; action.Kind == cutscene.WaitInput && as.Started {
+				as.Ended = true
+			}
+		}
+	}
+	state.Waiting = false
+}
+
+// done returns if all the actions has ended
+func (cm *cutsceneManager) done(state cutscene.State) bool {
+	for _, actions := range state.Actions {
+		for _, as := range actions {
+			if !as.Ended {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// run starts, updates and ends the actions of a cutscene.Timeline up to the state time, or all of them when skipping
+func (cm *cutsceneManager) run(world *goecs.World, id goecs.EntityID, actors map[string]*goecs.Entity,
+	tl cutscene.Timeline, state *cutscene.State, skip bool) {
+	for _, ai := range cm.order(tl, skip) {
+		action := tl.Tracks[ai.track].Actions[ai.action]
+		as := &state.Actions[ai.track][ai.action]
+		if as.Ended || (!skip && action.Time > state.Time) {
+			continue
+		}
+		actor := actors[action.Actor]
+		if !as.Started {
+			as.Started = true
+			cm.start(world, id, action, actor, as, skip)
+		}
+
+		if action.Kind == cutscene.WaitInput {
+			if skip {
+				as.Ended = true
+			} else {
+				state.Waiting = true
+			}
+			continue
+		}
+
+		var progress float32 = 1
+		if !skip && action.Duration > 0 {
+			if progress = (state.Time - action.Time) / action.Duration; progress > 1 {
+				progress = 1
+			}
+		}
+		cm.update(action, actor, *as, progress)
+		if progress >= 1 {
+			as.Ended = true
+			cm.end(action, actor)
+		}
+	}
+}
+
+// actionIndex is the position of a cutscene.Action in a cutscene.Timeline
+type actionIndex struct {
+	track  int // track is the index of the cutscene.Track
+	action int // action is the index of the cutscene.Action in its track
+}
+
+// order returns the actions of a cutscene.Timeline in track order, when skipping they are sorted by when they end,
+// and then by when they start, so the actions of different tracks on the same actor end as they will do when playing
+func (cm *cutsceneManager) order(tl cutscene.Timeline, skip bool) []actionIndex {
+	result := make([]actionIndex, 0)
+	for ti, track := range tl.Tracks {
+		for ai := range track.Actions {
+			result = append(result, actionIndex{track: ti, action: ai})
+		}
+	}
+	if skip {
+		sort.SliceStable(result, func(i, j int) bool {
+			a := tl.Tracks[result[i].track].Actions[result[i].action]
+			b := tl.Tracks[result[j].track].Actions[result[j].action]
+			if a.Time+a.Duration != b.Time+b.Duration {
+				return a.Time+a.Duration < b.Time+b.Duration
+			}
+			return a.Time < b.Time
+		})
+	}
+	return result
+}
+
+// start begins a cutscene.Action, sounds are not played when skipping
+func (cm *cutsceneManager) start(world *goecs.World, id goecs.EntityID, action cutscene.Action, actor *goecs.Entity,
+	as *cutscene.ActionState, skip bool) {
+	volume := action.Volume
+	if volume == 0 {
+		volume = 1
+	}
+	switch action.Kind {
+	case cutscene.Move:
+		if actor != nil && actor.Contains(geometry.TYPE.Point) {
+			as.From = geometry.Get.Point(actor)
+		}
+	case cutscene.Animate:
+		if actor != nil && actor.Contains(animation.TYPE.Animation) {
+			anim := animation.Get.Animation(actor)
+			anim.Current = action.Name
+			actor.Set(anim)
+		}
+		if actor != nil && actor.Contains(skeleton.TYPE.Skeleton) {
+			skl := skeleton.Get.Skeleton(actor)
+			skl.Current = action.Name
+			actor.Set(skl)
+		}
+	case cutscene.Dialog:
+		if actor != nil && actor.Contains(ui.TYPE.Text) {
+			text := ui.Get.Text(actor)
+			text.String = action.Text
+			actor.Set(text)
+			actor.Remove(effects.TYPE.Hide)
+		}
+	case cutscene.Sound:
+		if !skip {
+			world.Signal(events.PlaySoundEvent{Name: action.Name, Volume: volume})
+		}
+	case cutscene.Music:
+		world.Signal(events.PlayMusicEvent{Name: action.Name, Volume: volume})
+	case cutscene.Signal:
+		if action.Signal != nil {
+			world.Signal(action.Signal)
+		} else {
+			world.Signal(events.CutsceneSignalEvent{Entity: id, Name: action.Name})
+		}
+	}
+}
+
+// update changes the actor of a running cutscene.Action for its progress
+func (cm *cutsceneManager) update(action cutscene.Action, actor *goecs.Entity, as cutscene.ActionState,
+	progress float32) {
+	if action.Kind == cutscene.Move && actor != nil {
+		t := action.Ease.Apply(progress)
+		actor.Set(geometry.Point{
+			X: as.From.X + ((action.Point.X - as.From.X) * t),
+			Y: as.From.Y + ((action.Point.Y - as.From.Y) * t),
+		})
+	}
+}
+
+// end finish a cutscene.Action, dialogs with a duration get hidden
+func (cm *cutsceneManager) end(action cutscene.Action, actor *goecs.Entity) {
+	if action.Kind == cutscene.Dialog && action.Duration > 0 && actor != nil {
+		actor.Set(effects.Hide{})
+	}
+}
+
+// Cutscenes returns a managers.WithSystemAndListener that plays cutscene.Timeline with a cutscene.Player
+func Cutscenes() WithSystemAndListener {
+	return &cutsceneManager{}
+}
@@ -26,12 +26,14 @@ import (
 	"github.com/juan-medina/gosge/components"
 	"github.com/juan-medina/gosge/components/color"
 	"github.com/juan-medina/gosge/components/device"
+	"github.com/juan-medina/gosge/components/effects"
 	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/juan-medina/gosge/components/shapes"
 	"github.com/juan-medina/gosge/components/sprite"
 	"github.com/juan-medina/gosge/components/ui"
 	"github.com/juan-medina/gosge/managers/ray"
 	"github.com/juan-medina/gosge/options"
+	"image"
 )
 
 //DeviceManager is the interface for our device manager
@@ -80,6 +82,8 @@ type DeviceManager interface {
 
 	// LoadTexture giving it file name into VRAM
 	LoadTexture(fileName string) (components.TextureDef, error)
+	// LoadTextureFromImage giving it an image.Image into VRAM
+	LoadTextureFromImage(img image.Image) (components.TextureDef, error)
 	// UnloadTexture from VRAM
 	UnloadTexture(textureDef components.TextureDef)
 
@@ -136,6 +140,14 @@ type DeviceManager interface {
 	BeginScissor(from geometry.Point, size geometry.Size)
 	// EndScissor end the current scissor
 	EndScissor()
+	// BeginBlendMode start drawing with the given effects.BlendMode
+	BeginBlendMode(mode effects.BlendMode)
+	// EndBlendMode ends drawing with a effects.BlendMode, and go back to effects.AlphaBlend
+	EndBlendMode()
+	// BeginCamera start drawing moved by an offset and zoomed from the center of the screen
+	BeginCamera(offset geometry.Point, zoom float32)
+	// EndCamera ends drawing with the camera, and go back to draw without offset and zoom
+	EndCamera()
 }
 
 // Device return the DeviceManager
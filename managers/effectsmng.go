@@ -26,13 +26,25 @@ import (
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge/components/color"
 	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/sprite"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"math"
 )
 
 type effectManager struct{}
 
 func (e effectManager) System(world *goecs.World, delta float32) error {
-	return e.alternateColorSystem(world, delta)
+	if err := e.alternateColorSystem(world, delta); err != nil {
+		return err
+	}
+	if err := e.parallaxSystem(world, delta); err != nil {
+		return err
+	}
+	return e.colorEffectsSystem(world, delta)
 }
+
 func (e effectManager) alternateColorSystem(world *goecs.World, delta float32) error {
 	for it := world.Iterator(effects.TYPE.AlternateColor); it != nil; it = it.Next() {
 		ent := it.Value()
@@ -82,6 +94,313 @@ func (e effectManager) alternateColorSystem(world *goecs.World, delta float32) e
 	return nil
 }
 
+func (e effectManager) parallaxSystem(world *goecs.World, delta float32) error {
+	for it := world.Iterator(effects.TYPE.Parallax); it != nil; it = it.Next() {
+		ent := it.Value()
+		parallax := effects.Get.Parallax(ent)
+
+		// get the camera position if we have any
+		var camera geometry.Point
+		if parallax.Camera != 0 {
+			if camEnt := world.Get(parallax.Camera); camEnt != nil && camEnt.ID() == parallax.Camera &&
+				camEnt.Contains(geometry.TYPE.Point) {
+				camera = geometry.Get.Point(camEnt)
+			}
+		}
+
+		// init the state or get it from entity
+		var state effects.ParallaxState
+		if ent.NotContains(effects.TYPE.ParallaxState) {
+			state = effects.ParallaxState{
+				Camera: camera,
+			}
+		} else {
+			state = effects.Get.ParallaxState(ent)
+		}
+
+		// scroll opposite to the camera movement
+		state.Offset.X -= (camera.X - state.Camera.X) * parallax.Factor.X
+		state.Offset.Y -= (camera.Y - state.Camera.Y) * parallax.Factor.Y
+		state.Camera = camera
+
+		// scroll with the velocity
+		state.Offset.X += parallax.Velocity.X * delta
+		state.Offset.Y += parallax.Velocity.Y * delta
+
+		ent.Set(state)
+	}
+	return nil
+}
+
+// finishedEffect is a color effect that has finish in this frame
+type finishedEffect struct {
+	ent    *goecs.Entity
+	effect goecs.ComponentType
+	state  goecs.ComponentType
+	event  interface{}
+}
+
+func (e effectManager) colorEffectsSystem(world *goecs.World, delta float32) error {
+	finished := make([]finishedEffect, 0)
+
+	for it := world.Iterator(effects.TYPE.Fade); it != nil; it = it.Next() {
+		ent := it.Value()
+		fade := effects.Get.Fade(ent)
+		var state effects.FadeState
+		if ent.Contains(effects.TYPE.FadeState) {
+			state = effects.Get.FadeState(ent)
+			state.Elapsed += delta
+		}
+		if state.Elapsed >= fade.Time {
+			// the colors keep the final alpha
+			fadeColors(ent, fade.To)
+			finished = append(finished, finishedEffect{
+				ent: ent, effect: effects.TYPE.Fade, state: effects.TYPE.FadeState, event: fade.Event,
+			})
+			continue
+		}
+		state.Progress = fade.Ease.Apply(effectProgress(state.Elapsed, fade.Time))
+		ent.Set(state)
+	}
+
+	for it := world.Iterator(effects.TYPE.Flash); it != nil; it = it.Next() {
+		ent := it.Value()
+		flash := effects.Get.Flash(ent)
+		var state effects.FlashState
+		if ent.Contains(effects.TYPE.FlashState) {
+			state = effects.Get.FlashState(ent)
+			state.Elapsed += delta
+		}
+		if state.Elapsed >= flash.Time {
+			finished = append(finished, finishedEffect{
+				ent: ent, effect: effects.TYPE.Flash, state: effects.TYPE.FlashState, event: flash.Event,
+			})
+			continue
+		}
+		state.Amount = 1 - flash.Ease.Apply(effectProgress(state.Elapsed, flash.Time))
+		ent.Set(state)
+	}
+
+	for it := world.Iterator(effects.TYPE.Pulse); it != nil; it = it.Next() {
+		ent := it.Value()
+		pulse := effects.Get.Pulse(ent)
+		var state effects.PulseState
+		if ent.Contains(effects.TYPE.PulseState) {
+			state = effects.Get.PulseState(ent)
+			state.Elapsed += delta
+		}
+		if pulse.Duration > 0 && state.Elapsed >= pulse.Duration {
+			finished = append(finished, finishedEffect{
+				ent: ent, effect: effects.TYPE.Pulse, state: effects.TYPE.PulseState, event: pulse.Event,
+			})
+			continue
+		}
+		// go to the color in the first half of the pulse and back in the second
+		state.Amount = 0
+		if pulse.Time > 0 {
+			phase := float32(math.Mod(float64(state.Elapsed), float64(pulse.Time))) / pulse.Time
+			if phase < 0.5 {
+				state.Amount = pulse.Ease.Apply(phase * 2)
+			} else {
+				state.Amount = pulse.Ease.Apply((1 - phase) * 2)
+			}
+		}
+		ent.Set(state)
+	}
+
+	for it := world.Iterator(effects.TYPE.Blink); it != nil; it = it.Next() {
+		ent := it.Value()
+		blink := effects.Get.Blink(ent)
+		var state effects.BlinkState
+		if ent.Contains(effects.TYPE.BlinkState) {
+			state = effects.Get.BlinkState(ent)
+			state.Elapsed += delta
+		} else {
+			state = effects.BlinkState{Hidden: ent.Contains(effects.TYPE.Hide)}
+		}
+		if blink.Duration > 0 && state.Elapsed >= blink.Duration {
+			setHidden(ent, state.Hidden)
+			finished = append(finished, finishedEffect{
+				ent: ent, effect: effects.TYPE.Blink, state: effects.TYPE.BlinkState, event: blink.Event,
+			})
+			continue
+		}
+		// the easing warps the time, so the blinks could speed up or slow down
+		elapsed := state.Elapsed
+		if blink.Duration > 0 {
+			elapsed = blink.Ease.Apply(state.Elapsed/blink.Duration) * blink.Duration
+		}
+		hidden := false
+		if blink.Time > 0 {
+			hidden = math.Mod(float64(elapsed), float64(blink.Time)) >= float64(blink.Time/2)
+		}
+		setHidden(ent, hidden)
+		ent.Set(state)
+	}
+
+	for _, fe := range finished {
+		fe.ent.Remove(fe.effect)
+		fe.ent.Remove(fe.state)
+		world.Signal(events.ColorEffectFinishedEvent{Entity: fe.ent.ID(), Effect: fe.effect})
+		if fe.event != nil {
+			world.Signal(fe.event)
+		}
+	}
+
+	return nil
+}
+
+// setHidden adds or removes the effects.Hide of an entity
+func setHidden(ent *goecs.Entity, hidden bool) {
+	if hidden {
+		ent.Set(effects.Hide{})
+	} else {
+		ent.Remove(effects.TYPE.Hide)
+	}
+}
+
+// effectProgress returns the progress, from 0 to 1, of an effect that has run for an elapsed time of a total time
+func effectProgress(elapsed, total float32) float32 {
+	if total <= 0 || elapsed >= total {
+		return 1
+	}
+	return elapsed / total
+}
+
+// tintColor returns a color.Solid blended with a tint color.Solid, keeping its alpha
+func tintColor(clr, tint color.Solid, amount float32) color.Solid {
+	return color.Solid{
+		R: clampChannel(float32(clr.R) + ((float32(tint.R) - float32(clr.R)) * amount)),
+		G: clampChannel(float32(clr.G) + ((float32(tint.G) - float32(clr.G)) * amount)),
+		B: clampChannel(float32(clr.B) + ((float32(tint.B) - float32(clr.B)) * amount)),
+		A: clr.A,
+	}
+}
+
+// fadeAlpha returns a color.Solid with its alpha changed toward a final alpha by a progress from 0 to 1
+func fadeAlpha(clr color.Solid, to uint8, progress float32) color.Solid {
+	return clr.Alpha(clampChannel(float32(clr.A) + ((float32(to) - float32(clr.A)) * progress)))
+}
+
+// fadeColors sets the alpha of the current color components of an entity, sprites without them get a white
+// color.Solid unless they will be fully opaque
+func fadeColors(ent *goecs.Entity, to uint8) {
+	comps := colorComponents(ent)
+	if len(comps) == 0 {
+		if to == 255 || ent.NotContains(sprite.TYPE) {
+			return
+		}
+		comps = append(comps, color.White)
+	}
+	for _, comp := range paintColors(comps, func(clr color.Solid) color.Solid {
+		return fadeAlpha(clr, to, 1)
+	}) {
+		ent.Set(comp)
+	}
+}
+
+// colorPaint changes a color.Solid when an entity is draw, a nil colorPaint does not change it
+type colorPaint func(clr color.Solid) color.Solid
+
+// solid returns a painted color.Solid
+func (cp colorPaint) solid(clr color.Solid) color.Solid {
+	if cp == nil {
+		return clr
+	}
+	return cp(clr)
+}
+
+// gradient returns a painted color.Gradient
+func (cp colorPaint) gradient(gra color.Gradient) color.Gradient {
+	gra.From = cp.solid(gra.From)
+	gra.To = cp.solid(gra.To)
+	return gra
+}
+
+// button returns a painted ui.ButtonColor
+func (cp colorPaint) button(clr ui.ButtonColor) ui.ButtonColor {
+	clr.Solid = cp.solid(clr.Solid)
+	clr.Gradient = cp.gradient(clr.Gradient)
+	clr.Border = cp.solid(clr.Border)
+	clr.Text = cp.solid(clr.Text)
+	return clr
+}
+
+// progressBar returns a painted ui.ProgressBarColor
+func (cp colorPaint) progressBar(clr ui.ProgressBarColor) ui.ProgressBarColor {
+	clr.Solid = cp.solid(clr.Solid)
+	clr.Gradient = cp.gradient(clr.Gradient)
+	clr.Empty = cp.solid(clr.Empty)
+	clr.Border = cp.solid(clr.Border)
+	return clr
+}
+
+// effectColors returns the colorPaint for the color effects that an entity has running, or nil if it has none
+func effectColors(ent *goecs.Entity) colorPaint {
+	var paints []colorPaint
+	if ent.Contains(effects.TYPE.FadeState) {
+		fade, state := effects.Get.Fade(ent), effects.Get.FadeState(ent)
+		paints = append(paints, func(clr color.Solid) color.Solid {
+			return fadeAlpha(clr, fade.To, state.Progress)
+		})
+	}
+	if ent.Contains(effects.TYPE.FlashState) {
+		flash, state := effects.Get.Flash(ent), effects.Get.FlashState(ent)
+		paints = append(paints, func(clr color.Solid) color.Solid {
+			return tintColor(clr, flash.Color, state.Amount)
+		})
+	}
+	if ent.Contains(effects.TYPE.PulseState) {
+		pulse, state := effects.Get.Pulse(ent), effects.Get.PulseState(ent)
+		paints = append(paints, func(clr color.Solid) color.Solid {
+			return tintColor(clr, pulse.Color, state.Amount)
+		})
+	}
+	switch len(paints) {
+	case 0:
+		return nil
+	case 1:
+		return paints[0]
+	}
+	return func(clr color.Solid) color.Solid {
+		for _, paint := range paints {
+			clr = paint(clr)
+		}
+		return clr
+	}
+}
+
+// colorComponents returns the components that give color to an entity
+func colorComponents(ent *goecs.Entity) []goecs.Component {
+	comps := make([]goecs.Component, 0)
+	for _, ct := range []goecs.ComponentType{
+		color.TYPE.Solid, color.TYPE.Gradient, ui.TYPE.ButtonColor, ui.TYPE.ProgressBarColor,
+	} {
+		if ent.Contains(ct) {
+			comps = append(comps, ent.Get(ct))
+		}
+	}
+	return comps
+}
+
+// paintColors returns color components with their colors changed with a colorPaint
+func paintColors(comps []goecs.Component, paint colorPaint) []goecs.Component {
+	result := make([]goecs.Component, 0, len(comps))
+	for _, comp := range comps {
+		switch v := comp.(type) {
+		case color.Solid:
+			result = append(result, paint.solid(v))
+		case color.Gradient:
+			result = append(result, paint.gradient(v))
+		case ui.ButtonColor:
+			result = append(result, paint.button(v))
+		case ui.ProgressBarColor:
+			result = append(result, paint.progressBar(v))
+		}
+	}
+	return result
+}
+
 // Effects is manager.WithSystem that handle effects
 func Effects() WithSystem {
 	return &effectManager{}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"fmt"
+	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/animation"
+	"github.com/juan-medina/gosge/components/geometry"
+	"image"
+	"image/draw"
+	"image/gif"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+const (
+	gifMinDelay     = 2    // gifMinDelay is the minimum GIF delay, in 100ths of second, that browsers respect
+	gifDefaultDelay = 10   // gifDefaultDelay is the GIF delay, in 100ths of second, used for shorter delays
+	gifPlayOnce     = -1   // gifPlayOnce is the GIF loop count for playing the animation once
+	gifDelayUnit    = 100  // gifDelayUnit is how many GIF delay units are in a second
+	gifMaxAtlasSize = 4096 // gifMaxAtlasSize is a fixed, conservative, cap for the width and height of a GIF atlas
+)
+
+// gifFrameName returns the sprite name for a frame of a GIF
+func gifFrameName(name string, frame int) string {
+	return fmt.Sprintf("%s_%d", filepath.Base(name), frame+1)
+}
+
+// composeGIF returns the frames of a GIF as full images, composed one over the other following their disposal
+func composeGIF(g *gif.GIF) []*image.RGBA {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	canvas := image.NewRGBA(bounds)
+	frames := make([]*image.RGBA, 0, len(g.Image))
+
+	for i, img := range g.Image {
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		// keep what we have to restore it after this frame
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			draw.Draw(previous, bounds, canvas, image.Point{}, draw.Src)
+		}
+
+		draw.Draw(canvas, img.Bounds(), img, img.Bounds().Min, draw.Over)
+
+		frame := image.NewRGBA(bounds)
+		draw.Draw(frame, bounds, canvas, image.Point{}, draw.Src)
+		frames = append(frames, frame)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, img.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return frames
+}
+
+// packGIF returns an atlas with the frames of a GIF in a grid and the position of each frame, or an error if they
+// do not fit in the GIF atlas size cap, that is not the device texture limit but one that most GPUs support
+func packGIF(frames []*image.RGBA, size image.Point) (*image.RGBA, []image.Point, error) {
+	cols := int(math.Ceil(math.Sqrt(float64(len(frames)))))
+	// use as many columns as fit, so there are less rows
+	if size.X > 0 && cols*size.X > gifMaxAtlasSize {
+		cols = gifMaxAtlasSize / size.X
+	}
+	if cols == 0 {
+		return nil, nil, fmt.Errorf("frames of %dx%d are bigger than the GIF atlas size cap of %dx%d",
+			size.X, size.Y, gifMaxAtlasSize, gifMaxAtlasSize)
+	}
+	rows := (len(frames) + cols - 1) / cols
+	if rows*size.Y > gifMaxAtlasSize {
+		return nil, nil, fmt.Errorf("%d frames of %dx%d do not fit in the GIF atlas size cap of %dx%d",
+			len(frames), size.X, size.Y, gifMaxAtlasSize, gifMaxAtlasSize)
+	}
+	atlas := image.NewRGBA(image.Rect(0, 0, cols*size.X, rows*size.Y))
+	positions := make([]image.Point, len(frames))
+
+	for i, frame := range frames {
+		positions[i] = image.Point{X: (i % cols) * size.X, Y: (i / cols) * size.Y}
+		draw.Draw(atlas, image.Rectangle{Min: positions[i], Max: positions[i].Add(size)}, frame, image.Point{},
+			draw.Src)
+	}
+
+	return atlas, positions, nil
+}
+
+// LoadGIF preloads an animated GIF as sprite sheet with a sprite per frame, and an animation.Sequence that use the
+// GIF frame delays, with the GIF file name
+func (sm *StorageManager) LoadGIF(name string) (err error) {
+	var file *os.File
+	if file, err = os.Open(name); err != nil {
+		return
+	}
+	//goland:noinspection GoUnhandledErrorResult
+	defer file.Close()
+
+	var g *gif.GIF
+	if g, err = gif.DecodeAll(file); err != nil {
+		return
+	}
+	if len(g.Image) == 0 {
+		return fmt.Errorf("gif %q has no frames", name)
+	}
+
+	frames := composeGIF(g)
+	size := image.Point{X: g.Config.Width, Y: g.Config.Height}
+	atlas, positions, err := packGIF(frames, size)
+	if err != nil {
+		return fmt.Errorf("can not load gif %q: %v", name, err)
+	}
+
+	var texture components.TextureDef
+	if texture, err = sm.dm.LoadTextureFromImage(atlas); err != nil {
+		return
+	}
+	if old, ok := sm.textures[name]; ok {
+		sm.dm.UnloadTexture(old)
+	}
+	sm.textures[name] = texture
+
+	st := make(spriteSheet, 0)
+	sm.sheets[name] = st
+	seq := animation.Sequence{
+		Sheet:     name,
+		Scale:     1,
+		FrameList: make([]animation.Frame, len(frames)),
+	}
+	if g.LoopCount == gifPlayOnce {
+		seq.Mode = animation.Once
+	}
+
+	for i, pos := range positions {
+		frameName := gifFrameName(name, i)
+		st[frameName] = components.SpriteDef{
+			Texture: texture,
+			Origin: geometry.Rect{
+				From: geometry.Point{
+					X: float32(pos.X),
+					Y: float32(pos.Y),
+				},
+				Size: geometry.Size{
+					Width:  float32(size.X),
+					Height: float32(size.Y),
+				},
+			},
+			Pivot: geometry.Point{X: 0.5, Y: 0.5},
+		}
+
+		delay := gifDefaultDelay
+		if i < len(g.Delay) && g.Delay[i] >= gifMinDelay {
+			delay = g.Delay[i]
+		}
+		seq.FrameList[i] = animation.Frame{
+			Name:     frameName,
+			Duration: float32(delay) / gifDelayUnit,
+		}
+	}
+	seq.Frames = int32(len(seq.FrameList))
+	seq.Delay = seq.FrameList[0].Duration
+	sm.sequences[name] = map[string]animation.Sequence{name: seq}
+
+	return
+}
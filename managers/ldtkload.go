@@ -0,0 +1,298 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/geometry"
+	"io/ioutil"
+	"path/filepath"
+)
+
+const (
+	ldtkIntGridLayer  = "IntGrid"   // ldtkIntGridLayer is a LDtk layer of int values, that may have auto-layer tiles
+	ldtkEntitiesLayer = "Entities"  // ldtkEntitiesLayer is a LDtk layer of entity instances
+	ldtkTilesLayer    = "Tiles"     // ldtkTilesLayer is a LDtk layer of tiles
+	ldtkAutoLayer     = "AutoLayer" // ldtkAutoLayer is a LDtk layer of tiles from the rules of another layer
+	ldtkFlipXBit      = 1           // ldtkFlipXBit is the bit that indicates that a LDtk tile is flipped horizontally
+	ldtkFlipYBit      = 2           // ldtkFlipYBit is the bit that indicates that a LDtk tile is flipped vertically
+)
+
+// ldtkProjectData is the LDtk project JSON
+type ldtkProjectData struct {
+	Defs struct {
+		Tilesets []ldtkTilesetData `json:"tilesets"`
+		Layers   []struct {
+			UID           int `json:"uid"`
+			IntGridValues []struct {
+				Value      int    `json:"value"`
+				Identifier string `json:"identifier"`
+			} `json:"intGridValues"`
+		} `json:"layers"`
+	} `json:"defs"`
+	Levels []ldtkLevelData `json:"levels"`
+}
+
+// ldtkTilesetData is a LDtk tileset definition
+type ldtkTilesetData struct {
+	UID      int     `json:"uid"`
+	RelPath  *string `json:"relPath"`
+	GridSize int     `json:"tileGridSize"`
+	Spacing  int     `json:"spacing"`
+	Padding  int     `json:"padding"`
+	PxWid    int     `json:"pxWid"`
+	PxHei    int     `json:"pxHei"`
+}
+
+// ldtkLevelData is a LDtk level, that may be in its own file
+type ldtkLevelData struct {
+	Identifier      string          `json:"identifier"`
+	WorldX          int             `json:"worldX"`
+	WorldY          int             `json:"worldY"`
+	PxWid           int             `json:"pxWid"`
+	PxHei           int             `json:"pxHei"`
+	ExternalRelPath *string         `json:"externalRelPath"`
+	FieldInstances  []ldtkFieldData `json:"fieldInstances"`
+	LayerInstances  []ldtkLayerData `json:"layerInstances"`
+}
+
+// ldtkFieldData is a LDtk field instance
+type ldtkFieldData struct {
+	Identifier string      `json:"__identifier"`
+	Value      interface{} `json:"__value"`
+}
+
+// ldtkLayerData is a LDtk layer instance
+type ldtkLayerData struct {
+	Identifier      string           `json:"__identifier"`
+	Type            string           `json:"__type"`
+	CWid            int              `json:"__cWid"`
+	CHei            int              `json:"__cHei"`
+	GridSize        int              `json:"__gridSize"`
+	Opacity         float32          `json:"__opacity"`
+	PxTotalOffsetX  int              `json:"__pxTotalOffsetX"`
+	PxTotalOffsetY  int              `json:"__pxTotalOffsetY"`
+	TilesetDefUID   *int             `json:"__tilesetDefUid"`
+	LayerDefUID     int              `json:"layerDefUid"`
+	Visible         *bool            `json:"visible"`
+	IntGridCsv      []int            `json:"intGridCsv"`
+	GridTiles       []ldtkTileData   `json:"gridTiles"`
+	AutoLayerTiles  []ldtkTileData   `json:"autoLayerTiles"`
+	EntityInstances []ldtkEntityData `json:"entityInstances"`
+}
+
+// ldtkTileData is a LDtk tile instance
+type ldtkTileData struct {
+	Px [2]int   `json:"px"`
+	F  int      `json:"f"`
+	T  int      `json:"t"`
+	A  *float32 `json:"a"`
+}
+
+// ldtkEntityData is a LDtk entity instance
+type ldtkEntityData struct {
+	Identifier     string          `json:"__identifier"`
+	IID            string          `json:"iid"`
+	Px             [2]int          `json:"px"`
+	Pivot          [2]float32      `json:"__pivot"`
+	Width          int             `json:"width"`
+	Height         int             `json:"height"`
+	Tags           []string        `json:"__tags"`
+	Tile           *ldtkTileRect   `json:"__tile"`
+	FieldInstances []ldtkFieldData `json:"fieldInstances"`
+}
+
+// ldtkTileRect is an area of a LDtk tileset
+type ldtkTileRect struct {
+	TilesetUID int `json:"tilesetUid"`
+	X          int `json:"x"`
+	Y          int `json:"y"`
+	W          int `json:"w"`
+	H          int `json:"h"`
+}
+
+// ldtkTileSpriteName returns the sprite.Sprite name in the project sheet for a tile in a tileset
+func ldtkTileSpriteName(tilesetUID, id int) string {
+	return fmt.Sprintf("%d:%d", tilesetUID, id)
+}
+
+// ldtkRectSpriteName returns the sprite.Sprite name in the project sheet for an area of a tileset
+func ldtkRectSpriteName(rect ldtkTileRect) string {
+	return fmt.Sprintf("%d:%d,%d,%d,%d", rect.TilesetUID, rect.X, rect.Y, rect.W, rect.H)
+}
+
+// ldtkFields returns the values of LDtk field instances by identifier
+func ldtkFields(fields []ldtkFieldData) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		result[f.Identifier] = f.Value
+	}
+	return result
+}
+
+// loadLDtkProject loads a LDtk project, adding a sprite sheet with the same name with the tiles of its tilesets
+func (sm *StorageManager) loadLDtkProject(name string) (result components.LDtkProjectDef, err error) {
+	var content []byte
+	if content, err = ioutil.ReadFile(name); err != nil {
+		return
+	}
+	var data ldtkProjectData
+	if err = json.Unmarshal(content, &data); err != nil {
+		return
+	}
+	dir := filepath.Dir(name)
+
+	// the tiles for all the tilesets go into a sheet
+	st := make(spriteSheet, 0)
+	tilesets := make(map[int]components.TextureDef)
+	for _, ts := range data.Defs.Tilesets {
+		// tilesets without path are the LDtk internal icons
+		if ts.RelPath == nil || ts.GridSize <= 0 {
+			continue
+		}
+		var texture components.TextureDef
+		if texture, err = sm.loadTexture(filepath.Join(dir, *ts.RelPath)); err != nil {
+			return
+		}
+		tilesets[ts.UID] = texture
+		cols := (ts.PxWid - (ts.Padding * 2) + ts.Spacing) / (ts.GridSize + ts.Spacing)
+		rows := (ts.PxHei - (ts.Padding * 2) + ts.Spacing) / (ts.GridSize + ts.Spacing)
+		for i := 0; i < cols*rows; i++ {
+			st[ldtkTileSpriteName(ts.UID, i)] = components.SpriteDef{
+				Texture: texture,
+				Origin: geometry.Rect{
+					From: geometry.Point{
+						X: float32(ts.Padding + ((i % cols) * (ts.GridSize + ts.Spacing))),
+						Y: float32(ts.Padding + ((i / cols) * (ts.GridSize + ts.Spacing))),
+					},
+					Size: geometry.Size{
+						Width:  float32(ts.GridSize),
+						Height: float32(ts.GridSize),
+					},
+				},
+			}
+		}
+	}
+	sm.sheets[name] = st
+
+	// the names for the int grid values
+	intGridNames := make(map[int]map[int]string)
+	for _, l := range data.Defs.Layers {
+		names := make(map[int]string)
+		for _, v := range l.IntGridValues {
+			names[v.Value] = v.Identifier
+		}
+		intGridNames[l.UID] = names
+	}
+
+	result.Levels = make(map[string]components.LDtkLevelDef, len(data.Levels))
+	for _, level := range data.Levels {
+		// levels may be saved in their own files
+		if level.LayerInstances == nil && level.ExternalRelPath != nil {
+			if content, err = ioutil.ReadFile(filepath.Join(dir, *level.ExternalRelPath)); err != nil {
+				return
+			}
+			if err = json.Unmarshal(content, &level); err != nil {
+				return
+			}
+		}
+		def := components.LDtkLevelDef{
+			Identifier:    level.Identifier,
+			WorldPosition: geometry.Point{X: float32(level.WorldX), Y: float32(level.WorldY)},
+			Size:          geometry.Size{Width: float32(level.PxWid), Height: float32(level.PxHei)},
+			Fields:        ldtkFields(level.FieldInstances),
+		}
+		// LDtk layers are from top to bottom, we want them in draw order
+		for i := len(level.LayerInstances) - 1; i >= 0; i-- {
+			def.Layers = append(def.Layers, sm.ldtkLayer(level.LayerInstances[i], intGridNames, tilesets, st))
+		}
+		result.Levels[level.Identifier] = def
+	}
+	return
+}
+
+// ldtkLayer returns the components.LDtkLayerDef for a LDtk layer instance
+func (sm *StorageManager) ldtkLayer(layer ldtkLayerData, intGridNames map[int]map[int]string,
+	tilesets map[int]components.TextureDef, st spriteSheet) components.LDtkLayerDef {
+	def := components.LDtkLayerDef{
+		Identifier:   layer.Identifier,
+		Type:         layer.Type,
+		GridSize:     float32(layer.GridSize),
+		Cols:         layer.CWid,
+		Rows:         layer.CHei,
+		Offset:       geometry.Point{X: float32(layer.PxTotalOffsetX), Y: float32(layer.PxTotalOffsetY)},
+		Opacity:      layer.Opacity,
+		Visible:      layer.Visible == nil || *layer.Visible,
+		IntGrid:      layer.IntGridCsv,
+		IntGridNames: intGridNames[layer.LayerDefUID],
+	}
+
+	if layer.TilesetDefUID != nil {
+		for _, tiles := range [][]ldtkTileData{layer.AutoLayerTiles, layer.GridTiles} {
+			for _, t := range tiles {
+				tile := components.LDtkTileDef{
+					ID:       t.T,
+					Sprite:   ldtkTileSpriteName(*layer.TilesetDefUID, t.T),
+					Position: geometry.Point{X: float32(t.Px[0]), Y: float32(t.Px[1])},
+					FlipX:    t.F&ldtkFlipXBit != 0,
+					FlipY:    t.F&ldtkFlipYBit != 0,
+					Alpha:    1,
+				}
+				if t.A != nil {
+					tile.Alpha = *t.A
+				}
+				def.Tiles = append(def.Tiles, tile)
+			}
+		}
+	}
+
+	for _, e := range layer.EntityInstances {
+		entity := components.LDtkEntityDef{
+			IID:        e.IID,
+			Identifier: e.Identifier,
+			Position:   geometry.Point{X: float32(e.Px[0]), Y: float32(e.Px[1])},
+			Size:       geometry.Size{Width: float32(e.Width), Height: float32(e.Height)},
+			Pivot:      geometry.Point{X: e.Pivot[0], Y: e.Pivot[1]},
+			Tags:       e.Tags,
+			Fields:     ldtkFields(e.FieldInstances),
+		}
+		// entities with a tile get a sprite for that area of the tileset
+		if e.Tile != nil {
+			if texture, ok := tilesets[e.Tile.TilesetUID]; ok {
+				entity.Sprite = ldtkRectSpriteName(*e.Tile)
+				st[entity.Sprite] = components.SpriteDef{
+					Texture: texture,
+					Origin: geometry.Rect{
+						From: geometry.Point{X: float32(e.Tile.X), Y: float32(e.Tile.Y)},
+						Size: geometry.Size{Width: float32(e.Tile.W), Height: float32(e.Tile.H)},
+					},
+					Pivot: entity.Pivot,
+				}
+			}
+		}
+		def.Entities = append(def.Entities, entity)
+	}
+	return def
+}
@@ -0,0 +1,357 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"fmt"
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/ldtk"
+	"github.com/juan-medina/gosge/components/sprite"
+	"math"
+)
+
+type ldtkManager struct {
+	sm        *StorageManager
+	factories map[string]ldtk.EntityFactory
+	entities  map[goecs.EntityID]levelEntities // entities are the entities that has been added for each level entity
+}
+
+// levelEntities are the tiles and entities that has been added for a level entity
+type levelEntities map[goecs.EntityID]bool
+
+// track adds an entity to the ones added for a level entity
+func (lm ldtkManager) track(levelID, id goecs.EntityID) {
+	entities, ok := lm.entities[levelID]
+	if !ok {
+		entities = make(levelEntities)
+		lm.entities[levelID] = entities
+	}
+	entities[id] = true
+}
+
+func (lm ldtkManager) System(world *goecs.World, _ float32) (err error) {
+	if err = lm.pruneLevels(world); err != nil {
+		return
+	}
+	for it := world.Iterator(ldtk.TYPE.Level, geometry.TYPE.Point); it != nil; it = it.Next() {
+		ent := it.Value()
+		level := ldtk.Get.Level(ent)
+		pos := geometry.Get.Point(ent)
+		if ent.NotContains(ldtk.TYPE.LevelState) {
+			depth := DefaultLayer
+			if ent.Contains(effects.TYPE.Layer) {
+				depth = effects.Get.Layer(ent).Depth
+			}
+			// the level state is set even if we fail, so what has been added is not added again
+			delete(lm.entities, ent.ID())
+			err = lm.addLayers(world, ent.ID(), level, depth, pos)
+			ent.Set(ldtk.LevelState{
+				Position: pos,
+				Scale:    level.Scale,
+			})
+			if err != nil {
+				return
+			}
+		} else {
+			state := ldtk.Get.LevelState(ent)
+			if state.Position.X != pos.X || state.Position.Y != pos.Y || state.Scale != level.Scale {
+				lm.updateEntities(world, ent.ID(), state, pos, level.Scale)
+				state.Position = pos
+				state.Scale = level.Scale
+				ent.Set(state)
+			}
+		}
+	}
+	return
+}
+
+// addLayers adds the entities for each layer of a ldtk.Level, each layer in its own depth starting from the level
+// depth
+func (lm ldtkManager) addLayers(world *goecs.World, levelID goecs.EntityID, level ldtk.Level, depth float32,
+	levelPos geometry.Point) (err error) {
+	var def components.LDtkLevelDef
+	if def, err = lm.sm.GetLDtkLevelDef(level.Project, level.Name); err != nil {
+		return
+	}
+
+	for i, layer := range def.Layers {
+		ld := depth - float32(i)
+		base := geometry.Point{
+			X: levelPos.X + (layer.Offset.X * level.Scale),
+			Y: levelPos.Y + (layer.Offset.Y * level.Scale),
+		}
+		if layer.Visible {
+			lm.addTiles(world, levelID, level, layer, ld, base)
+		}
+		// we keep adding the rest of the level and return the first error
+		if entErr := lm.addEntities(world, levelID, level, layer, ld, base); entErr != nil && err == nil {
+			err = entErr
+		}
+	}
+	return
+}
+
+// addTiles adds a sprite entity per each tile in a layer
+func (lm ldtkManager) addTiles(world *goecs.World, levelID goecs.EntityID, level ldtk.Level,
+	layer components.LDtkLayerDef, depth float32, base geometry.Point) {
+	for _, tile := range layer.Tiles {
+		info := ldtk.TileInfo{
+			Project: level.Project,
+			Level:   level.Name,
+			Layer:   layer.Identifier,
+			ID:      tile.ID,
+		}
+		if layer.GridSize > 0 {
+			info.Col = int(tile.Position.X / layer.GridSize)
+			info.Row = int(tile.Position.Y / layer.GridSize)
+		}
+		id := world.AddEntity(
+			sprite.Sprite{
+				Sheet: level.Project,
+				Name:  tile.Sprite,
+				Scale: level.Scale,
+				FlipX: tile.FlipX,
+				FlipY: tile.FlipY,
+			},
+			info,
+			geometry.Point{
+				X: base.X + (tile.Position.X * level.Scale),
+				Y: base.Y + (tile.Position.Y * level.Scale),
+			},
+			effects.Layer{Depth: depth},
+		)
+		lm.track(levelID, id)
+		if alpha := layer.Opacity * tile.Alpha; alpha < 1 {
+			world.Get(id).Add(color.White.Alpha(uint8(alpha * 255)))
+		}
+	}
+}
+
+// addEntities adds an entity per each entity instance in a layer, entities in hidden layers are added but their
+// sprites are hidden
+func (lm ldtkManager) addEntities(world *goecs.World, levelID goecs.EntityID, level ldtk.Level,
+	layer components.LDtkLayerDef, depth float32, base geometry.Point) (err error) {
+	for _, e := range layer.Entities {
+		info := ldtk.EntityInfo{
+			IID:        e.IID,
+			Identifier: e.Identifier,
+			Project:    level.Project,
+			Level:      level.Name,
+			Layer:      layer.Identifier,
+			Size:       e.Size.Scale(level.Scale),
+			Pivot:      e.Pivot,
+			Tags:       e.Tags,
+			Fields:     e.Fields,
+		}
+		pos := geometry.Point{
+			X: base.X + (e.Position.X * level.Scale),
+			Y: base.Y + (e.Position.Y * level.Scale),
+		}
+		id := world.AddEntity(info, pos)
+		lm.track(levelID, id)
+		ent := world.Get(id)
+
+		// entities with a tile are draw with it fitting their size
+		if e.Sprite != "" {
+			spr := sprite.Sprite{
+				Sheet: level.Project,
+				Name:  e.Sprite,
+				Scale: level.Scale,
+			}
+			if def, err := lm.sm.GetSpriteDef(spr.Sheet, spr.Name); err == nil && def.Origin.Size.Width > 0 {
+				spr.Scale = level.Scale * e.Size.Width / def.Origin.Size.Width
+			}
+			ent.Add(spr).Add(effects.Layer{Depth: depth})
+			if layer.Opacity < 1 {
+				ent.Add(color.White.Alpha(uint8(layer.Opacity * 255)))
+			}
+			if !layer.Visible {
+				ent.Add(effects.Hide{})
+			}
+		}
+
+		// we keep adding the rest of the entities and return the first error
+		if factory, ok := lm.factories[info.Identifier]; ok {
+			if factoryErr := factory(world, ent); factoryErr != nil && err == nil {
+				err = fmt.Errorf("error creating LDtk entity %q of identifier %q: %v", info.IID, info.Identifier,
+					factoryErr)
+			}
+		}
+	}
+	return
+}
+
+// updateEntities moves and scales the tiles and entities added for a level entity, from its previous state to a
+// new position and scale
+func (lm ldtkManager) updateEntities(world *goecs.World, levelID goecs.EntityID, state ldtk.LevelState,
+	pos geometry.Point, scale float32) {
+	ratio := float32(1)
+	if state.Scale != 0 {
+		ratio = scale / state.Scale
+	}
+	entities := lm.entities[levelID]
+	for id := range entities {
+		ent := world.Get(id)
+		if ent == nil || ent.ID() != id || ent.NotContains(geometry.TYPE.Point) {
+			// the entity has been removed
+			delete(entities, id)
+			continue
+		}
+		at := geometry.Get.Point(ent)
+		ent.Set(geometry.Point{
+			X: pos.X + ((at.X - state.Position.X) * ratio),
+			Y: pos.Y + ((at.Y - state.Position.Y) * ratio),
+		})
+		if ratio == 1 {
+			continue
+		}
+		if ent.Contains(sprite.TYPE) {
+			spr := sprite.Get(ent)
+			spr.Scale *= ratio
+			ent.Set(spr)
+		}
+		if ent.Contains(ldtk.TYPE.EntityInfo) {
+			info := ldtk.Get.EntityInfo(ent)
+			info.Size = info.Size.Scale(ratio)
+			ent.Set(info)
+		}
+	}
+}
+
+// pruneLevels removes the tiles and entities added for the level entities that has been removed
+func (lm ldtkManager) pruneLevels(world *goecs.World) error {
+	for levelID, entities := range lm.entities {
+		if ent := world.Get(levelID); ent != nil && ent.ID() == levelID && ent.Contains(ldtk.TYPE.Level) {
+			continue
+		}
+		for id := range entities {
+			if ent := world.Get(id); ent != nil && ent.ID() == id {
+				if err := world.Remove(id); err != nil {
+					return err
+				}
+			}
+		}
+		delete(lm.entities, levelID)
+	}
+	return nil
+}
+
+// getLevel returns the ldtk.Level, its geometry.Point and its components.LDtkLevelDef from a level entity
+func (lm ldtkManager) getLevel(world *goecs.World, levelID goecs.EntityID) (level ldtk.Level, pos geometry.Point,
+	def components.LDtkLevelDef, err error) {
+	ent := world.Get(levelID)
+	if ent == nil || ent.ID() != levelID || ent.NotContains(ldtk.TYPE.Level) || ent.NotContains(geometry.TYPE.Point) {
+		err = fmt.Errorf("entity %d is not a LDtk level", levelID)
+		return
+	}
+	level = ldtk.Get.Level(ent)
+	pos = geometry.Get.Point(ent)
+	def, err = lm.sm.GetLDtkLevelDef(level.Project, level.Name)
+	return
+}
+
+// findIntGridLayer returns an IntGrid layer with a given identifier
+func findIntGridLayer(def components.LDtkLevelDef, name string) (components.LDtkLayerDef, error) {
+	for _, layer := range def.Layers {
+		if layer.Identifier == name && layer.Type == ldtkIntGridLayer {
+			return layer, nil
+		}
+	}
+	return components.LDtkLayerDef{}, fmt.Errorf("can not find IntGrid layer %q in LDtk level %q", name,
+		def.Identifier)
+}
+
+// GetIntGrid returns the value of a cell in an IntGrid layer of a level entity, 0 for empty cells or cells outside
+// the layer, and the identifier of the value if it has one
+func (lm ldtkManager) GetIntGrid(world *goecs.World, levelID goecs.EntityID, layer string, col, row int) (value int,
+	name string, err error) {
+	var def components.LDtkLevelDef
+	if _, _, def, err = lm.getLevel(world, levelID); err != nil {
+		return
+	}
+	var ld components.LDtkLayerDef
+	if ld, err = findIntGridLayer(def, layer); err != nil {
+		return
+	}
+	if col < 0 || row < 0 || col >= ld.Cols || row >= ld.Rows {
+		return
+	}
+	if i := (row * ld.Cols) + col; i < len(ld.IntGrid) {
+		value = ld.IntGrid[i]
+		name = ld.IntGridNames[value]
+	}
+	return
+}
+
+// WorldToCell returns the col and row in a layer of a level entity in a world geometry.Point
+func (lm ldtkManager) WorldToCell(world *goecs.World, levelID goecs.EntityID, layer string,
+	at geometry.Point) (col, row int, err error) {
+	var level ldtk.Level
+	var pos geometry.Point
+	var def components.LDtkLevelDef
+	if level, pos, def, err = lm.getLevel(world, levelID); err != nil {
+		return
+	}
+	for _, ld := range def.Layers {
+		if ld.Identifier != layer {
+			continue
+		}
+		if ld.GridSize <= 0 || level.Scale <= 0 {
+			break
+		}
+		x := ((at.X-pos.X)/level.Scale - ld.Offset.X) / ld.GridSize
+		y := ((at.Y-pos.Y)/level.Scale - ld.Offset.Y) / ld.GridSize
+		return int(math.Floor(float64(x))), int(math.Floor(float64(y))), nil
+	}
+	err = fmt.Errorf("can not find layer %q in LDtk level %q", layer, def.Identifier)
+	return
+}
+
+// RegisterEntityFactory register a ldtk.EntityFactory for the entity instances of a given identifier
+func (lm ldtkManager) RegisterEntityFactory(identifier string, factory ldtk.EntityFactory) {
+	lm.factories[identifier] = factory
+}
+
+// LDtkLeveler is a manager that handle LDtk levels
+type LDtkLeveler interface {
+	WithSystem
+	// GetIntGrid returns the value, and its identifier, of a cell in an IntGrid layer of a level entity
+	GetIntGrid(world *goecs.World, levelID goecs.EntityID, layer string, col, row int) (int, string, error)
+	// WorldToCell returns the col and row in a layer of a level entity in a world geometry.Point
+	WorldToCell(world *goecs.World, levelID goecs.EntityID, layer string, at geometry.Point) (int, int, error)
+	// RegisterEntityFactory register a ldtk.EntityFactory for the entity instances of a given identifier
+	RegisterEntityFactory(identifier string, factory ldtk.EntityFactory)
+}
+
+// LDtkLevels returns a managers.LDtkLeveler that handle LDtk levels
+func LDtkLevels(sm *StorageManager) LDtkLeveler {
+	return ldtkManager{
+		sm:        sm,
+		factories: make(map[string]ldtk.EntityFactory),
+		entities:  make(map[goecs.EntityID]levelEntities),
+	}
+}
@@ -0,0 +1,528 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/juan-medina/gosge/components"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultPluralRule  = "n != 1" // defaultPluralRule is the plural rule when we do not have one
+	poContextSeparator = "|"      // poContextSeparator separates the msgctxt and the msgid in the key of a PO entry
+)
+
+// pluralRules are the plural rules, as gettext expressions, for languages that do not use the default rule
+var pluralRules = map[string]string{
+	"fr": "n > 1",
+	"pt": "n > 1",
+	"ja": "0",
+	"ko": "0",
+	"zh": "0",
+	"vi": "0",
+	"th": "0",
+	"id": "0",
+	"ru": "n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2",
+	"uk": "n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2",
+	"pl": "n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2",
+	"cs": "n==1 ? 0 : (n>=2 && n<=4) ? 1 : 2",
+	"sk": "n==1 ? 0 : (n>=2 && n<=4) ? 1 : 2",
+}
+
+// localeData is the JSON format for a locale
+type localeData struct {
+	Language string                     `json:"language"`
+	Plural   string                     `json:"plural"`
+	Texts    map[string]json.RawMessage `json:"texts"`
+}
+
+// loadLocale loads a locale from a JSON or PO file
+func loadLocale(name string) (result components.LocaleDef, err error) {
+	var bytes []byte
+	if bytes, err = ioutil.ReadFile(name); err != nil {
+		return
+	}
+
+	var plural string
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".po":
+		result, plural, err = parsePOLocale(string(bytes))
+	case ".json":
+		result, plural, err = parseJSONLocale(bytes)
+	default:
+		err = fmt.Errorf("unsupported locale format %q", name)
+	}
+	if err != nil {
+		return
+	}
+
+	if result.Language == "" {
+		base := filepath.Base(name)
+		result.Language = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	if plural == "" {
+		plural = defaultPluralRule
+		lang := strings.ToLower(result.Language)
+		if i := strings.IndexAny(lang, "_-"); i != -1 {
+			lang = lang[:i]
+		}
+		if rule, ok := pluralRules[lang]; ok {
+			plural = rule
+		}
+	}
+
+	result.Plural, err = parsePluralRule(plural)
+	return
+}
+
+func parseJSONLocale(bytes []byte) (result components.LocaleDef, plural string, err error) {
+	data := localeData{}
+	if err = json.Unmarshal(bytes, &data); err != nil {
+		return
+	}
+
+	result.Language = data.Language
+	result.Texts = make(map[string][]string, len(data.Texts))
+	plural = data.Plural
+
+	for key, raw := range data.Texts {
+		var single string
+		var forms []string
+		if err = json.Unmarshal(raw, &single); err == nil {
+			forms = []string{single}
+		} else if err = json.Unmarshal(raw, &forms); err != nil {
+			err = fmt.Errorf("invalid text for key %q, it should be a string or a list of strings", key)
+			return
+		}
+		result.Texts[key] = forms
+	}
+
+	return
+}
+
+// poEntry is an entry being parsed on a PO file
+type poEntry struct {
+	context string
+	id      string
+	plural  string
+	forms   map[int]string
+	fuzzy   bool    // fuzzy indicates that the translation is marked as fuzzy, so it should not be used
+	last    *string // last is the string that continuation lines are added to
+	form    int     // form is the plural form that continuation lines are added to, -1 if none
+}
+
+func (pe poEntry) key() string {
+	if pe.context != "" {
+		return pe.context + poContextSeparator + pe.id
+	}
+	return pe.id
+}
+
+func (pe poEntry) texts() []string {
+	size := 0
+	for form := range pe.forms {
+		if form >= size {
+			size = form + 1
+		}
+	}
+	texts := make([]string, size)
+	for i := range texts {
+		texts[i] = pe.forms[i]
+	}
+	return texts
+}
+
+func parsePOLocale(content string) (result components.LocaleDef, plural string, err error) {
+	result.Texts = make(map[string][]string)
+
+	entry := poEntry{forms: make(map[int]string), form: -1}
+	flush := func() {
+		if len(entry.forms) > 0 {
+			if entry.id == "" {
+				// the header
+				language, rule := parsePOHeader(entry.forms[0])
+				result.Language = language
+				plural = rule
+			} else if entry.forms[0] != "" && !entry.fuzzy {
+				result.Texts[entry.key()] = entry.texts()
+			}
+		}
+		entry = poEntry{forms: make(map[int]string), form: -1}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(text, "#") {
+			// comments belong to the next entry
+			if len(entry.forms) > 0 {
+				flush()
+			}
+			if strings.HasPrefix(text, "#,") {
+				for _, flag := range strings.Split(text[2:], ",") {
+					if strings.TrimSpace(flag) == "fuzzy" {
+						entry.fuzzy = true
+					}
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(text, "\"") {
+			// continuation of the previous string
+			var str string
+			if str, err = strconv.Unquote(text); err != nil {
+				err = fmt.Errorf("invalid po file, bad string at line %d: %v", line, err)
+				return
+			}
+			if entry.form != -1 {
+				entry.forms[entry.form] += str
+			} else if entry.last != nil {
+				*entry.last += str
+			} else {
+				err = fmt.Errorf("invalid po file, unexpected string at line %d", line)
+				return
+			}
+			continue
+		}
+
+		keyword := text
+		value := ""
+		if i := strings.IndexFunc(text, unicode.IsSpace); i != -1 {
+			keyword = text[:i]
+			value = strings.TrimSpace(text[i:])
+		}
+
+		var str string
+		if str, err = strconv.Unquote(value); err != nil {
+			err = fmt.Errorf("invalid po file, bad string at line %d: %v", line, err)
+			return
+		}
+
+		entry.last = nil
+		entry.form = -1
+
+		switch {
+		case keyword == "msgctxt":
+			if entry.id != "" || len(entry.forms) > 0 {
+				flush()
+			}
+			entry.context = str
+			entry.last = &entry.context
+		case keyword == "msgid":
+			if entry.id != "" || len(entry.forms) > 0 {
+				flush()
+			}
+			entry.id = str
+			entry.last = &entry.id
+		case keyword == "msgid_plural":
+			entry.plural = str
+			entry.last = &entry.plural
+		case keyword == "msgstr":
+			entry.forms[0] = str
+			entry.form = 0
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			var form int
+			if form, err = strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1]); err != nil {
+				err = fmt.Errorf("invalid po file, bad plural form at line %d", line)
+				return
+			}
+			entry.forms[form] = str
+			entry.form = form
+		default:
+			err = fmt.Errorf("invalid po file, unknown keyword %q at line %d", keyword, line)
+			return
+		}
+	}
+	flush()
+
+	err = scanner.Err()
+	return
+}
+
+func parsePOHeader(header string) (language string, plural string) {
+	for _, line := range strings.Split(header, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		switch strings.ToLower(name) {
+		case "language":
+			language = value
+		case "plural-forms":
+			for _, field := range strings.Split(value, ";") {
+				field = strings.TrimSpace(field)
+				if strings.HasPrefix(field, "plural=") {
+					plural = strings.TrimPrefix(field, "plural=")
+				}
+			}
+		}
+	}
+	return
+}
+
+// pluralExpr is a compiled plural expression
+type pluralExpr func(n int) int
+
+// pluralParser is a parser for gettext plural expressions, a subset of C expressions using the variable n
+type pluralParser struct {
+	tokens []string
+	pos    int
+}
+
+func (pp *pluralParser) peek() string {
+	if pp.pos < len(pp.tokens) {
+		return pp.tokens[pp.pos]
+	}
+	return ""
+}
+
+func (pp *pluralParser) next() string {
+	tk := pp.peek()
+	pp.pos++
+	return tk
+}
+
+func (pp *pluralParser) expect(tk string) error {
+	if got := pp.next(); got != tk {
+		return fmt.Errorf("invalid plural rule, got %q, want %q", got, tk)
+	}
+	return nil
+}
+
+// ternary : or ( '?' ternary ':' ternary )?
+func (pp *pluralParser) ternary() (pluralExpr, error) {
+	cond, err := pp.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	if pp.peek() != "?" {
+		return cond, nil
+	}
+	pp.next()
+	var yes, no pluralExpr
+	if yes, err = pp.ternary(); err != nil {
+		return nil, err
+	}
+	if err = pp.expect(":"); err != nil {
+		return nil, err
+	}
+	if no, err = pp.ternary(); err != nil {
+		return nil, err
+	}
+	return func(n int) int {
+		if cond(n) != 0 {
+			return yes(n)
+		}
+		return no(n)
+	}, nil
+}
+
+// pluralPrecedence are the binary operators by precedence, from lower to higher
+var pluralPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func pluralOperation(op string, left, right pluralExpr) pluralExpr {
+	return func(n int) int {
+		a := left(n)
+		switch op {
+		case "||":
+			return boolToInt(a != 0 || right(n) != 0)
+		case "&&":
+			return boolToInt(a != 0 && right(n) != 0)
+		}
+		b := right(n)
+		switch op {
+		case "==":
+			return boolToInt(a == b)
+		case "!=":
+			return boolToInt(a != b)
+		case "<":
+			return boolToInt(a < b)
+		case "<=":
+			return boolToInt(a <= b)
+		case ">":
+			return boolToInt(a > b)
+		case ">=":
+			return boolToInt(a >= b)
+		case "+":
+			return a + b
+		case "-":
+			return a - b
+		case "*":
+			return a * b
+		case "/":
+			if b == 0 {
+				return 0
+			}
+			return a / b
+		case "%":
+			if b == 0 {
+				return 0
+			}
+			return a % b
+		}
+		return 0
+	}
+}
+
+func (pp *pluralParser) binary(level int) (pluralExpr, error) {
+	if level == len(pluralPrecedence) {
+		return pp.unary()
+	}
+	left, err := pp.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := pp.peek()
+		found := false
+		for _, candidate := range pluralPrecedence[level] {
+			if op == candidate {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return left, nil
+		}
+		pp.next()
+		var right pluralExpr
+		if right, err = pp.binary(level + 1); err != nil {
+			return nil, err
+		}
+		left = pluralOperation(op, left, right)
+	}
+}
+
+func (pp *pluralParser) unary() (pluralExpr, error) {
+	tk := pp.next()
+	switch {
+	case tk == "!":
+		expr, err := pp.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int) int { return boolToInt(expr(n) == 0) }, nil
+	case tk == "-":
+		expr, err := pp.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n int) int { return -expr(n) }, nil
+	case tk == "(":
+		expr, err := pp.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if err = pp.expect(")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case tk == "n":
+		return func(n int) int { return n }, nil
+	case tk != "" && unicode.IsDigit(rune(tk[0])):
+		v, err := strconv.Atoi(tk)
+		if err != nil {
+			return nil, err
+		}
+		return func(int) int { return v }, nil
+	}
+	return nil, fmt.Errorf("invalid plural rule, unexpected %q", tk)
+}
+
+func tokenizePluralRule(rule string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(rule); {
+		c := rule[i]
+		switch {
+		case c == ' ' || c == '\t' || c == ';':
+			i++
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(rule) && rule[j] >= '0' && rule[j] <= '9' {
+				j++
+			}
+			tokens = append(tokens, rule[i:j])
+			i = j
+		case c == 'n':
+			tokens = append(tokens, "n")
+			i++
+		case strings.ContainsRune("=!<>&|", rune(c)) && i+1 < len(rule) &&
+			(rule[i+1] == '=' || (c == '&' && rule[i+1] == '&') || (c == '|' && rule[i+1] == '|')):
+			tokens = append(tokens, rule[i:i+2])
+			i += 2
+		case strings.ContainsRune("!<>+-*/%?:()", rune(c)):
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			return nil, fmt.Errorf("invalid plural rule %q, unexpected %q", rule, c)
+		}
+	}
+	return tokens, nil
+}
+
+// parsePluralRule compiles a gettext plural expression, ex: n != 1
+func parsePluralRule(rule string) (func(n int) int, error) {
+	tokens, err := tokenizePluralRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	pp := &pluralParser{tokens: tokens}
+	expr, err := pp.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if pp.pos != len(pp.tokens) {
+		return nil, fmt.Errorf("invalid plural rule %q, unexpected %q", rule, pp.peek())
+	}
+	return expr, nil
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPluralRules(t *testing.T) {
+	tests := []struct {
+		language string
+		counts   []int
+		forms    []int
+	}{
+		{"en", []int{0, 1, 2, 5, 21}, []int{1, 0, 1, 1, 1}},
+		{"fr", []int{0, 1, 2, 5, 21}, []int{0, 0, 1, 1, 1}},
+		{"ja", []int{0, 1, 2, 5, 21}, []int{0, 0, 0, 0, 0}},
+		{"ru", []int{1, 2, 5, 11, 21, 22, 25, 111, 112}, []int{0, 1, 2, 2, 0, 1, 2, 2, 2}},
+		{"pl", []int{1, 2, 5, 12, 21, 22, 25, 102}, []int{0, 1, 2, 2, 2, 1, 2, 1}},
+		{"cs", []int{0, 1, 2, 4, 5, 22}, []int{2, 0, 1, 1, 2, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			rule, ok := pluralRules[tt.language]
+			if !ok {
+				rule = defaultPluralRule
+			}
+			plural, err := parsePluralRule(rule)
+			if err != nil {
+				t.Fatalf("parsePluralRule(%q) error = %v", rule, err)
+			}
+			for i, n := range tt.counts {
+				if got := plural(n); got != tt.forms[i] {
+					t.Errorf("plural(%d) = %d, want %d", n, got, tt.forms[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParsePluralRule(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		n    int
+		want int
+	}{
+		{"number", "2", 7, 2},
+		{"variable", "n", 7, 7},
+		{"multiplication before addition", "1 + n * 2", 3, 7},
+		{"parenthesis", "(1 + n) * 2", 3, 8},
+		{"modulo", "n % 10", 23, 3},
+		{"division by zero", "n / 0", 3, 0},
+		{"comparison before and", "n > 1 && n < 5", 3, 1},
+		{"and before or", "n == 0 || n == 1 && n == 2", 0, 1},
+		{"not", "!(n == 1)", 1, 0},
+		{"negative", "-n + 5", 2, 3},
+		{"ternary", "n == 1 ? 10 : 20", 1, 10},
+		{"ternary else", "n == 1 ? 10 : 20", 2, 20},
+		{"nested ternary", "n == 1 ? 0 : n == 2 ? 1 : 2", 2, 1},
+		{"nested ternary last", "n == 1 ? 0 : n == 2 ? 1 : 2", 3, 2},
+		{"trailing semicolon", "n != 1;", 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plural, err := parsePluralRule(tt.rule)
+			if err != nil {
+				t.Fatalf("parsePluralRule(%q) error = %v", tt.rule, err)
+			}
+			if got := plural(tt.n); got != tt.want {
+				t.Errorf("parsePluralRule(%q)(%d) = %d, want %d", tt.rule, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePluralRuleErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{"empty", ""},
+		{"unknown variable", "x > 1"},
+		{"missing operand", "n >"},
+		{"missing parenthesis", "(n > 1"},
+		{"extra parenthesis", "n > 1)"},
+		{"missing else", "n == 1 ? 0"},
+		{"trailing tokens", "n 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parsePluralRule(tt.rule); err == nil {
+				t.Errorf("parsePluralRule(%q) expected error, got nil", tt.rule)
+			}
+		})
+	}
+}
+
+func TestParsePOLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		language string
+		plural   string
+		texts    map[string][]string
+		wantErr  bool
+	}{
+		{
+			name: "header",
+			content: `msgid ""
+msgstr ""
+"Language: es\n"
+"Plural-Forms: nplurals=2; plural=n != 1;\n"
+`,
+			language: "es",
+			plural:   "n != 1",
+			texts:    map[string][]string{},
+		},
+		{
+			name: "multiline",
+			content: `msgid ""
+"hello "
+"world"
+msgstr "hola "
+"mundo"
+`,
+			texts: map[string][]string{"hello world": {"hola mundo"}},
+		},
+		{
+			name: "escapes",
+			content: `msgid "say \"hi\"\n"
+msgstr "di \"hola\"\t\n"
+`,
+			texts: map[string][]string{"say \"hi\"\n": {"di \"hola\"\t\n"}},
+		},
+		{
+			name: "plural forms",
+			content: `msgid "apple"
+msgid_plural "apples"
+msgstr[0] "manzana"
+msgstr[1] "manzanas"
+`,
+			texts: map[string][]string{"apple": {"manzana", "manzanas"}},
+		},
+		{
+			name: "sparse plural forms",
+			content: `msgid "apple"
+msgid_plural "apples"
+msgstr[0] "one"
+msgstr[2] "many"
+`,
+			texts: map[string][]string{"apple": {"one", "", "many"}},
+		},
+		{
+			name: "context",
+			content: `msgctxt "menu"
+msgid "open"
+msgstr "abrir"
+
+msgid "open"
+msgstr "abierto"
+`,
+			texts: map[string][]string{"menu" + poContextSeparator + "open": {"abrir"}, "open": {"abierto"}},
+		},
+		{
+			name: "fuzzy",
+			content: `#, fuzzy
+msgid "open"
+msgstr "abrir"
+
+#, c-format, fuzzy
+msgid "close"
+msgstr "cerrar"
+
+#, c-format
+msgid "save"
+msgstr "guardar"
+`,
+			texts: map[string][]string{"save": {"guardar"}},
+		},
+		{
+			name: "fuzzy without blank line",
+			content: `msgid "save"
+msgstr "guardar"
+#, fuzzy
+msgid "open"
+msgstr "abrir"
+`,
+			texts: map[string][]string{"save": {"guardar"}},
+		},
+		{
+			name: "untranslated",
+			content: `msgid "open"
+msgstr ""
+`,
+			texts: map[string][]string{},
+		},
+		{
+			name:    "bad string",
+			content: `msgid "open`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown keyword",
+			content: `msgfoo "open"`,
+			wantErr: true,
+		},
+		{
+			name: "unexpected string",
+			content: `"open"
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, plural, err := parsePOLocale(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("parsePOLocale() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePOLocale() error = %v", err)
+			}
+			if result.Language != tt.language {
+				t.Errorf("parsePOLocale() language = %q, want %q", result.Language, tt.language)
+			}
+			if plural != tt.plural {
+				t.Errorf("parsePOLocale() plural = %q, want %q", plural, tt.plural)
+			}
+			if !reflect.DeepEqual(result.Texts, tt.texts) {
+				t.Errorf("parsePOLocale() texts = %q, want %q", result.Texts, tt.texts)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"fmt"
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+	"github.com/juan-medina/gosge/options"
+	"github.com/rs/zerolog/log"
+	"reflect"
+	"strings"
+)
+
+const (
+	languageSetting  = "language" // languageSetting is the in game setting that store the current language
+	countPlaceholder = "count"    // countPlaceholder is the placeholder that will be replaced with the count
+)
+
+type localizationManager struct {
+	sm       *StorageManager
+	settings options.Settings
+}
+
+func (lm localizationManager) Signals() []goecs.ComponentType {
+	return []goecs.ComponentType{
+		events.TYPE.ChangeLanguageEvent,
+	}
+}
+
+func (lm localizationManager) System(world *goecs.World, _ float32) error {
+	language := lm.Language()
+	for it := world.Iterator(ui.TYPE.LocalizedText); it != nil; it = it.Next() {
+		ent := it.Value()
+		lt := ui.Get.LocalizedText(ent)
+
+		if ent.Contains(ui.TYPE.LocalizedTextState) {
+			state := ui.Get.LocalizedTextState(ent)
+			if state.Language == language && sameLocalizedText(state.Resolved, lt) {
+				continue
+			}
+		}
+
+		if ent.Contains(ui.TYPE.Text) {
+			text := ui.Get.Text(ent)
+			text.String = lm.Localize(language, lt)
+			ent.Set(text)
+		}
+
+		ent.Set(ui.LocalizedTextState{
+			Language: language,
+			Resolved: lt,
+		})
+	}
+	return nil
+}
+
+func (lm localizationManager) Listener(_ *goecs.World, event goecs.Component, _ float32) error {
+	switch e := event.(type) {
+	case events.ChangeLanguageEvent:
+		// an unknown language should not stop the game, so we keep the current one
+		if _, err := lm.sm.GetLocaleDef(e.Language); err != nil {
+			log.Warn().Err(err).Str("language", e.Language).Msg("can not change language")
+			return nil
+		}
+		lm.settings.SetString(languageSetting, e.Language)
+	}
+	return nil
+}
+
+// sameLocalizedText returns if two ui.LocalizedText will be translated the same, args that can not be compared are
+// taken as different
+func sameLocalizedText(a, b ui.LocalizedText) bool {
+	if a.Key != b.Key || a.Context != b.Context || a.Count != b.Count || len(a.Args) != len(b.Args) {
+		return false
+	}
+	for k, v := range a.Args {
+		other, ok := b.Args[k]
+		if !ok || (v != nil && !reflect.TypeOf(v).Comparable()) || v != other {
+			return false
+		}
+	}
+	return true
+}
+
+// Language returns the current language
+func (lm localizationManager) Language() string {
+	return lm.settings.GetString(languageSetting, lm.sm.GetDefaultLanguage())
+}
+
+// Localize translate a ui.LocalizedText to a given language, if the key is not found in that language it will use
+// the default language, and if is not there either it will return the key
+func (lm localizationManager) Localize(language string, lt ui.LocalizedText) string {
+	key := lt.Key
+	if lt.Context != "" {
+		key = lt.Context + poContextSeparator + lt.Key
+	}
+	forms, plural := lm.find(language, key)
+	if forms == nil {
+		forms, plural = lm.find(lm.sm.GetDefaultLanguage(), key)
+	}
+
+	result := lt.Key
+	if forms != nil {
+		form := 0
+		if plural != nil {
+			form = plural(lt.Count)
+		}
+		if form < 0 {
+			form = 0
+		} else if form >= len(forms) {
+			form = len(forms) - 1
+		}
+		result = forms[form]
+	}
+
+	if strings.Contains(result, "{") {
+		result = strings.ReplaceAll(result, "{"+countPlaceholder+"}", fmt.Sprint(lt.Count))
+		for k, v := range lt.Args {
+			result = strings.ReplaceAll(result, "{"+k+"}", fmt.Sprint(v))
+		}
+	}
+
+	return result
+}
+
+func (lm localizationManager) find(language string, key string) ([]string, func(n int) int) {
+	if def, err := lm.sm.GetLocaleDef(language); err == nil {
+		if forms, ok := def.Texts[key]; ok && len(forms) > 0 {
+			return forms, def.Plural
+		}
+	}
+	return nil, nil
+}
+
+// Localizer is a manager that translate ui.LocalizedText
+type Localizer interface {
+	WithSystemAndListener
+	// Language returns the current language
+	Language() string
+	// Localize translate a ui.LocalizedText to a given language
+	Localize(language string, lt ui.LocalizedText) string
+}
+
+// Localization returns a managers.Localizer that translate ui.LocalizedText to the language stored in the settings
+func Localization(sm *StorageManager, settings options.Settings) Localizer {
+	return &localizationManager{
+		sm:       sm,
+		settings: settings,
+	}
+}
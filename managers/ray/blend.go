@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package ray
+
+import (
+	"github.com/gen2brain/raylib-go/raylib"
+	"github.com/juan-medina/gosge/components/effects"
+)
+
+// BeginBlendMode start drawing with the given effects.BlendMode
+func (dmi DeviceManagerImpl) BeginBlendMode(mode effects.BlendMode) {
+	switch mode {
+	case effects.AdditiveBlend:
+		rl.BeginBlendMode(rl.BlendAdditive)
+	case effects.MultiplyBlend:
+		rl.BeginBlendMode(rl.BlendMultiplied)
+	case effects.SubtractBlend:
+		rl.BeginBlendMode(rl.BlendSubtractColors)
+	default:
+		rl.BeginBlendMode(rl.BlendAlpha)
+	}
+}
+
+// EndBlendMode ends drawing with a effects.BlendMode, and go back to effects.AlphaBlend
+func (dmi DeviceManagerImpl) EndBlendMode() {
+	rl.EndBlendMode()
+}
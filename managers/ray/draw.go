@@ -31,6 +31,7 @@ import (
 	"github.com/juan-medina/gosge/components/shapes"
 	"github.com/juan-medina/gosge/components/sprite"
 	"github.com/juan-medina/gosge/components/ui"
+	"image"
 )
 
 func (dmi *DeviceManagerImpl) color2RayColor(color color.Solid) rl.Color {
@@ -50,6 +51,16 @@ func (dmi DeviceManagerImpl) LoadTexture(fileName string) (components.TextureDef
 	return emptyTexture, fmt.Errorf("error loading texture: %q", fileName)
 }
 
+// LoadTextureFromImage giving it an image.Image into VRAM
+func (dmi DeviceManagerImpl) LoadTextureFromImage(img image.Image) (components.TextureDef, error) {
+	ri := rl.NewImageFromImage(img)
+	defer rl.UnloadImage(ri)
+	if t := rl.LoadTextureFromImage(ri); t.ID != 0 {
+		return components.TextureDef{Data: t, Size: geometry.Size{Width: float32(t.Width), Height: float32(t.Height)}}, nil
+	}
+	return emptyTexture, fmt.Errorf("error loading texture from image")
+}
+
 // LoadFont giving it file name into VRAM
 func (dmi DeviceManagerImpl) LoadFont(fileName string) (components.FontDef, error) {
 	if f := rl.LoadFont(fileName); f.Texture.ID != 0 {
@@ -166,6 +177,21 @@ func (dmi DeviceManagerImpl) EndScissor() {
 	rl.EndScissorMode()
 }
 
+// BeginCamera start drawing moved by an offset and zoomed from the center of the screen
+func (dmi DeviceManagerImpl) BeginCamera(offset geometry.Point, zoom float32) {
+	center := rl.Vector2{
+		X: float32(rl.GetScreenWidth()) / 2,
+		Y: float32(rl.GetScreenHeight()) / 2,
+	}
+	origin := rl.Vector2{X: center.X + offset.X, Y: center.Y + offset.Y}
+	rl.BeginMode2D(rl.NewCamera2D(origin, center, 0, zoom))
+}
+
+// EndCamera ends drawing with the camera, and go back to draw without offset and zoom
+func (dmi DeviceManagerImpl) EndCamera() {
+	rl.EndMode2D()
+}
+
 // DrawBox draws a box outline with an color.Solid and a scale
 func (dmi DeviceManagerImpl) DrawBox(pos geometry.Point, box shapes.Box, solid color.Solid) {
 	rec := rl.Rectangle{
@@ -23,24 +23,69 @@
 package managers
 
 import (
+	"fmt"
 	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components"
 	"github.com/juan-medina/gosge/components/color"
 	"github.com/juan-medina/gosge/components/effects"
 	"github.com/juan-medina/gosge/components/geometry"
 	"github.com/juan-medina/gosge/components/shapes"
 	"github.com/juan-medina/gosge/components/sprite"
+	"github.com/juan-medina/gosge/components/tiled"
 	"github.com/juan-medina/gosge/components/ui"
+	"math"
 	"runtime"
+	"strings"
 )
 
 type renderingManager struct {
-	dm DeviceManager
-	sm *StorageManager
+	dm       DeviceManager
+	sm       *StorageManager
+	cem      CameraEffecter
+	scissors *scissorStack
+}
+
+// scissorStack keeps the scissors areas that we are using, so they could be nested
+type scissorStack struct {
+	areas []geometry.Rect // areas are the current areas, the last one is the one in use
+}
+
+// clipArea is the screen area for an effects.Clip
+type clipArea struct {
+	rect    geometry.Rect   // rect is the screen area
+	clipped effects.Clipped // clipped is the effects.Clipped of the effects.Clip entity, if it has it
+	nested  bool            // nested indicates that the effects.Clip entity is also effects.Clipped
 }
 
 var noTint = color.White
 
-func (rdm renderingManager) renderSprite(ent *goecs.Entity) error {
+// Don't use scissor on OSX until this is fix https://github.com/raysan5/raylib/issues/1281
+var useScissor = runtime.GOOS != "darwin"
+
+const (
+	textLineSpacing = 1.5  // textLineSpacing is the line height, relative to the text size, of a multi-line ui.Text
+	gradientBands   = 16   // gradientBands is how many bands we use to draw a vertical color.Gradient in a ui.Text
+	waveCharPhase   = 0.35 // waveCharPhase is the phase difference between characters on a ui.WaveTextAnimation
+)
+
+// outlineDirections are the directions that we use to draw a text outline
+var outlineDirections = []geometry.Point{
+	{X: -1, Y: -1}, {X: 0, Y: -1}, {X: 1, Y: -1},
+	{X: -1, Y: 0}, {X: 1, Y: 0},
+	{X: -1, Y: 1}, {X: 0, Y: 1}, {X: 1, Y: 1},
+}
+
+// maxClipNesting is how many effects.Clip could be nested inside each other
+const maxClipNesting = 16
+
+// styledChar is a character of a ui.Text with a ui.TextStyle ready to be drawn
+type styledChar struct {
+	txt ui.Text        // txt is the ui.Text for this character
+	pos geometry.Point // pos is the geometry.Point for this character
+	clr color.Solid    // clr is the fill color.Solid for this character
+}
+
+func (rdm renderingManager) renderSprite(ent *goecs.Entity, paint colorPaint) error {
 	spr := sprite.Get(ent)
 	pos := geometry.Get.Point(ent)
 
@@ -50,8 +95,12 @@ func (rdm renderingManager) renderSprite(ent *goecs.Entity) error {
 	} else {
 		tint = noTint
 	}
+	tint = paint.solid(tint)
 
 	if def, err := rdm.sm.GetSpriteDef(spr.Sheet, spr.Name); err == nil {
+		if ent.Contains(effects.TYPE.Parallax) {
+			return rdm.renderParallaxSprite(ent, def, spr, pos, tint)
+		}
 		if err := rdm.dm.DrawSprite(def, spr, pos, tint); err != nil {
 			return err
 		}
@@ -61,28 +110,159 @@ func (rdm renderingManager) renderSprite(ent *goecs.Entity) error {
 	return nil
 }
 
-func (rdm renderingManager) renderBox(ent *goecs.Entity) error {
+// parallaxStart returns where we start drawing a wrapped sprite so its copies cover the screen from 0
+func parallaxStart(pos, offset, size, pivot float32) float32 {
+	// the left edge of the first copy should be in (-size, 0]
+	edge := float32(math.Mod(float64(pos+offset-(pivot*size)), float64(size)))
+	if edge > 0 {
+		edge -= size
+	}
+	return edge + (pivot * size)
+}
+
+func (rdm renderingManager) renderParallaxSprite(ent *goecs.Entity, def components.SpriteDef, spr sprite.Sprite,
+	pos geometry.Point, tint color.Solid) error {
+	parallax := effects.Get.Parallax(ent)
+	var offset geometry.Point
+	if ent.Contains(effects.TYPE.ParallaxState) {
+		offset = effects.Get.ParallaxState(ent).Offset
+	}
+
+	size := def.Origin.Size.Scale(spr.Scale)
+	if size.Width <= 0 || size.Height <= 0 {
+		return nil
+	}
+	screen := rdm.dm.GetScreenSize()
+
+	// if we do not wrap in an axis we just scroll on it
+	from := pos.Add(offset)
+	to := from
+	if parallax.WrapX {
+		from.X = parallaxStart(pos.X, offset.X, size.Width, def.Pivot.X)
+		to.X = screen.Width + (def.Pivot.X * size.Width)
+	}
+	if parallax.WrapY {
+		from.Y = parallaxStart(pos.Y, offset.Y, size.Height, def.Pivot.Y)
+		to.Y = screen.Height + (def.Pivot.Y * size.Height)
+	}
+
+	for y := from.Y; y <= to.Y; y += size.Height {
+		for x := from.X; x <= to.X; x += size.Width {
+			if err := rdm.dm.DrawSprite(def, spr, geometry.Point{X: x, Y: y}, tint); err != nil {
+				return err
+			}
+			if !parallax.WrapX {
+				break
+			}
+		}
+		if !parallax.WrapY {
+			break
+		}
+	}
+	return nil
+}
+
+// renderTileChunk draws the tiles of a tiled.Chunk from its map data, only if the chunk is in the screen
+func (rdm renderingManager) renderTileChunk(world *goecs.World, ent *goecs.Entity, paint colorPaint) error {
+	chunk := tiled.Get.Chunk(ent)
+	mapEnt := world.Get(chunk.Map)
+	if mapEnt == nil || mapEnt.ID() != chunk.Map || mapEnt.NotContains(tiled.TYPE.Map) ||
+		mapEnt.NotContains(geometry.TYPE.Point) {
+		return nil
+	}
+	tiledMap := tiled.Get.Map(mapEnt)
+	mapPos := geometry.Get.Point(mapEnt)
+
+	mapDef, err := rdm.sm.GetTiledMapDef(tiledMap.Name)
+	if err != nil {
+		return err
+	}
+	if chunk.Layer < 0 || chunk.Layer >= len(mapDef.Layers) || mapDef.Layers[chunk.Layer].Tiles == nil {
+		return nil
+	}
+	layer := mapDef.Layers[chunk.Layer]
+
+	origin := mapPos
+	var animations map[uint32]tiled.TileAnimationState
+	if mapEnt.Contains(tiled.TYPE.MapState) {
+		state := tiled.Get.MapState(mapEnt)
+		origin, animations = state.Origin, state.Animations
+	}
+	base := layerPosition(layer, tiledMap.Scale, origin, mapPos)
+
+	bounds := geometry.Rect{
+		From: geometry.Point{
+			X: (chunk.Bounds.From.X * tiledMap.Scale) + base.X,
+			Y: (chunk.Bounds.From.Y * tiledMap.Scale) + base.Y,
+		},
+		Size: chunk.Bounds.Size.Scale(tiledMap.Scale),
+	}
+	screen := geometry.Rect{Size: rdm.dm.GetScreenSize()}
+	if cameraMoved(ent) {
+		screen = rdm.cameraView(rdm.cem.View())
+	}
+	if area := screen.Intersection(bounds); area.Size.Width <= 0 || area.Size.Height <= 0 {
+		return nil
+	}
+
+	tint := noTint
+	if ent.Contains(color.TYPE.Solid) {
+		tint = color.Get.Solid(ent)
+	}
+	tint = paint.solid(tint)
+
+	for _, index := range chunk.Cells {
+		col, row := index%mapDef.Data.Width, index/mapDef.Data.Width
+		tile := layerTile(mapDef, layer, col, row)
+		if tile.IsNil() {
+			continue
+		}
+		gid := tile.Tileset.FirstGID + tile.ID
+		if frames, ok := mapDef.Animations[gid]; ok && len(frames) > 0 {
+			gid = frames[animations[gid].Frame%len(frames)].GID
+		}
+		def, ok := mapDef.Sprites[gid]
+		if !ok {
+			return fmt.Errorf("can not find tile %d in tiled map %q", gid, tiledMap.Name)
+		}
+		spr := sprite.Sprite{
+			Scale: tiledMap.Scale,
+			FlipX: tile.HorizontalFlip,
+			FlipY: tile.VerticalFlip,
+		}
+		pos := tileToMap(mapDef, col, row)
+		pos = pos.Add(tileOffset(mapDef, def, tile.Tileset))
+		pos.X = (pos.X * tiledMap.Scale) + base.X
+		pos.Y = (pos.Y * tiledMap.Scale) + base.Y
+		if err := rdm.dm.DrawSprite(def, spr, pos, tint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rdm renderingManager) renderBox(ent *goecs.Entity, paint colorPaint) error {
 	pos := geometry.Get.Point(ent)
 	box := shapes.Get.Box(ent)
-	clr := color.Get.Solid(ent)
+	clr := paint.solid(color.Get.Solid(ent))
 	rdm.dm.DrawBox(pos, box, clr)
 	return nil
 }
 
-func (rdm renderingManager) renderSolidBox(ent *goecs.Entity) error {
+func (rdm renderingManager) renderSolidBox(ent *goecs.Entity, paint colorPaint) error {
 	pos := geometry.Get.Point(ent)
 	box := shapes.Get.SolidBox(ent)
 	if ent.Contains(color.TYPE.Solid) {
-		clr := color.Get.Solid(ent)
+		clr := paint.solid(color.Get.Solid(ent))
 		rdm.dm.DrawSolidBox(pos, box, clr)
 	} else if ent.Contains(color.TYPE.Gradient) {
-		gra := color.Get.Gradient(ent)
+		gra := paint.gradient(color.Get.Gradient(ent))
 		rdm.dm.DrawGradientBox(pos, box, gra)
 	}
 	return nil
 }
 
-func (rdm renderingManager) renderLine(ent *goecs.Entity) error {
+func (rdm renderingManager) renderLine(ent *goecs.Entity, paint colorPaint) error {
 	pos := geometry.Get.Point(ent)
 	line := shapes.Get.Line(ent)
 	clr := color.White
@@ -91,16 +271,16 @@ func (rdm renderingManager) renderLine(ent *goecs.Entity) error {
 		clr = color.Get.Solid(ent)
 	}
 
-	rdm.dm.DrawLine(pos, line.To, line.Thickness, clr)
+	rdm.dm.DrawLine(pos, line.To, line.Thickness, paint.solid(clr))
 
 	return nil
 }
 
-func (rdm renderingManager) renderFlatButton(ent *goecs.Entity) error {
+func (rdm renderingManager) renderFlatButton(ent *goecs.Entity, paint colorPaint) error {
 	pos := geometry.Get.Point(ent)
 	box := shapes.Get.Box(ent)
 	fb := ui.Get.FlatButton(ent)
-	clr := ui.Get.ButtonColor(ent)
+	clr := paint.button(ui.Get.ButtonColor(ent))
 	state := ui.Get.ControlState(ent)
 
 	sb := shapes.SolidBox{
@@ -184,11 +364,11 @@ func (rdm renderingManager) renderFlatButton(ent *goecs.Entity) error {
 	return nil
 }
 
-func (rdm renderingManager) renderProgressBar(v *goecs.Entity) error {
+func (rdm renderingManager) renderProgressBar(v *goecs.Entity, paint colorPaint) error {
 	box := shapes.Get.Box(v)
 	pos := geometry.Get.Point(v)
 	pro := ui.Get.ProgressBar(v)
-	clr := ui.Get.ProgressBarColor(v)
+	clr := paint.progressBar(ui.Get.ProgressBarColor(v))
 
 	sb := shapes.SolidBox{
 		Size:  box.Size,
@@ -209,9 +389,6 @@ func (rdm renderingManager) renderProgressBar(v *goecs.Entity) error {
 	total := pro.Max - pro.Min
 	per := pro.Current / total
 
-	// Don't use scissor on OSX until this is fix https://github.com/raysan5/raylib/issues/1281
-	useScissor := runtime.GOOS != "darwin"
-
 	if useScissor {
 		// draw background
 		rdm.dm.DrawSolidBox(pos, sb, clr.Empty)
@@ -222,7 +399,7 @@ func (rdm renderingManager) renderProgressBar(v *goecs.Entity) error {
 			if per < 1 {
 				size := box.Size.Scale(box.Scale)
 				size.Width = size.Width * per
-				rdm.dm.BeginScissor(pos, size)
+				rdm.beginScissor(pos, size)
 			}
 
 			// draw fill
@@ -234,7 +411,7 @@ func (rdm renderingManager) renderProgressBar(v *goecs.Entity) error {
 
 			// if we need scissor
 			if per < 1 {
-				rdm.dm.EndScissor()
+				rdm.endScissor()
 			}
 		}
 	} else {
@@ -272,14 +449,18 @@ func (rdm renderingManager) renderProgressBar(v *goecs.Entity) error {
 	return nil
 }
 
-func (rdm renderingManager) renderText(v *goecs.Entity) error {
+func (rdm renderingManager) renderText(v *goecs.Entity, paint colorPaint) error {
 	textCmp := ui.Get.Text(v)
 	posCmp := geometry.Get.Point(v)
-	colorCmp := color.Get.Solid(v)
+	colorCmp := paint.solid(color.Get.Solid(v))
 
 	if ftd, err := rdm.sm.GetFontDef(textCmp.Font); err == nil {
-		// draw the text
-		rdm.dm.DrawText(ftd, textCmp, posCmp, colorCmp)
+		if v.Contains(ui.TYPE.TextStyle) {
+			rdm.renderStyledText(ftd, v, textCmp, posCmp, colorCmp)
+		} else {
+			// draw the text
+			rdm.dm.DrawText(ftd, textCmp, posCmp, colorCmp)
+		}
 	} else {
 		return err
 	}
@@ -287,8 +468,182 @@ func (rdm renderingManager) renderText(v *goecs.Entity) error {
 	return nil
 }
 
+func (rdm renderingManager) renderStyledText(ftd components.FontDef, ent *goecs.Entity, txt ui.Text,
+	pos geometry.Point, clr color.Solid) {
+	style := ui.Get.TextStyle(ent)
+
+	var state ui.TextStyleState
+	if ent.Contains(ui.TYPE.TextStyleState) {
+		state = ui.Get.TextStyleState(ent)
+	}
+
+	chars := rdm.layoutStyledText(ftd, txt, style, state, pos, clr)
+
+	// draw shadow
+	if style.Shadow.Width != 0 || style.Shadow.Height != 0 {
+		for _, c := range chars {
+			shadowPos := geometry.Point{
+				X: c.pos.X + style.Shadow.Width,
+				Y: c.pos.Y + style.Shadow.Height,
+			}
+			rdm.dm.DrawText(ftd, c.txt, shadowPos, style.ShadowColor)
+		}
+	}
+
+	// draw outline
+	if style.OutlineThickness > 0 {
+		for _, c := range chars {
+			for _, dir := range outlineDirections {
+				outlinePos := geometry.Point{
+					X: c.pos.X + (dir.X * style.OutlineThickness),
+					Y: c.pos.Y + (dir.Y * style.OutlineThickness),
+				}
+				rdm.dm.DrawText(ftd, c.txt, outlinePos, style.Outline)
+			}
+		}
+	}
+
+	// draw fill, vertical gradients are draw in bands
+	if useScissor && style.Gradient.Direction == color.GradientVertical &&
+		!style.Gradient.From.Equals(style.Gradient.To) {
+		rdm.renderTextVerticalGradient(ftd, chars, style)
+	} else {
+		for _, c := range chars {
+			rdm.dm.DrawText(ftd, c.txt, c.pos, c.clr)
+		}
+	}
+}
+
+func (rdm renderingManager) renderTextVerticalGradient(ftd components.FontDef, chars []styledChar, style ui.TextStyle) {
+	if len(chars) == 0 {
+		return
+	}
+
+	// calculate the area that the characters use
+	from := chars[0].pos
+	to := chars[0].pos
+	for _, c := range chars {
+		size := rdm.dm.MeasureText(ftd, c.txt.String, c.txt.Size)
+		from.X = float32(math.Min(float64(from.X), float64(c.pos.X)))
+		from.Y = float32(math.Min(float64(from.Y), float64(c.pos.Y)))
+		to.X = float32(math.Max(float64(to.X), float64(c.pos.X+size.Width)))
+		to.Y = float32(math.Max(float64(to.Y), float64(c.pos.Y+size.Height)))
+	}
+
+	bandSize := geometry.Size{
+		Width:  to.X - from.X,
+		Height: (to.Y - from.Y) / gradientBands,
+	}
+
+	for b := 0; b < gradientBands; b++ {
+		bandPos := geometry.Point{
+			X: from.X,
+			Y: from.Y + (float32(b) * bandSize.Height),
+		}
+		clr := style.Gradient.From.Blend(style.Gradient.To, float32(b)/(gradientBands-1))
+		rdm.beginScissor(bandPos, bandSize)
+		for _, c := range chars {
+			rdm.dm.DrawText(ftd, c.txt, c.pos, clr)
+		}
+		rdm.endScissor()
+	}
+}
+
+func (rdm renderingManager) layoutStyledText(ftd components.FontDef, txt ui.Text, style ui.TextStyle,
+	state ui.TextStyleState, pos geometry.Point, clr color.Solid) []styledChar {
+	// calculate where the text starts, as DrawText will do
+	origin := pos
+	if txt.HAlignment != ui.LeftHAlignment || txt.VAlignment != ui.BottomVAlignment {
+		size := rdm.dm.MeasureText(ftd, txt.String, txt.Size)
+		switch txt.HAlignment {
+		case ui.CenterHAlignment:
+			origin.X -= size.Width / 2
+		case ui.RightHAlignment:
+			origin.X -= size.Width
+		}
+		switch txt.VAlignment {
+		case ui.BottomVAlignment:
+			origin.Y -= size.Height
+		case ui.MiddleVAlignment:
+			origin.Y -= size.Height / 2
+		}
+	}
+
+	// the new lines are not characters, so they do not count for revealing or coloring them
+	runes := []rune(txt.String)
+	total := len(runes) - strings.Count(txt.String, "\n")
+	visible := total
+	if style.Animation == ui.TypewriterTextAnimation {
+		if shown := int(state.Time * style.Speed); shown < visible {
+			visible = shown
+		}
+	}
+
+	gradient := style.Gradient.Direction == color.GradientHorizontal && !style.Gradient.From.Equals(style.Gradient.To)
+
+	chars := make([]styledChar, 0, visible)
+	cursor := origin
+	i := 0
+	for _, r := range runes {
+		if i >= visible {
+			break
+		}
+		str := string(r)
+		if str == "\n" {
+			cursor.X = origin.X
+			cursor.Y += txt.Size * textLineSpacing
+			continue
+		}
+
+		c := styledChar{
+			txt: ui.Text{
+				String:     str,
+				Size:       txt.Size,
+				Font:       txt.Font,
+				HAlignment: ui.LeftHAlignment,
+				VAlignment: ui.TopVAlignment,
+			},
+			pos: cursor,
+			clr: clr,
+		}
+
+		if gradient && total > 1 {
+			c.clr = style.Gradient.From.Blend(style.Gradient.To, float32(i)/float32(total-1))
+		}
+
+		switch style.Animation {
+		case ui.WaveTextAnimation:
+			angle := (float64(state.Time*style.Speed) * 2 * math.Pi) + (float64(i) * waveCharPhase)
+			c.pos.Y += float32(math.Sin(angle)) * style.Amplitude
+		case ui.ShakeTextAnimation:
+			step := math.Floor(float64(state.Time * style.Speed))
+			c.pos.X += shakeNoise(step+(float64(i)*7.13)) * style.Amplitude
+			c.pos.Y += shakeNoise((step*1.7)+(float64(i)*3.37)+0.5) * style.Amplitude
+		}
+
+		chars = append(chars, c)
+		cursor.X += rdm.dm.MeasureText(ftd, str, txt.Size).Width
+		i++
+	}
+
+	return chars
+}
+
+// shakeNoise returns a pseudo random value between -1 and 1 for a given seed
+func shakeNoise(seed float64) float32 {
+	v := math.Sin(seed*12.9898) * 43758.5453
+	return float32(v-math.Floor(v))*2 - 1
+}
+
 func (rdm renderingManager) isRenderable(ent *goecs.Entity) bool {
-	return ent.Contains(geometry.TYPE.Point) && ent.NotContains(effects.TYPE.Hide) &&
+	if ent.Contains(effects.TYPE.Hide) {
+		return false
+	}
+	// tiled.Chunk are positioned by their map entity
+	if ent.Contains(tiled.TYPE.Chunk) {
+		return true
+	}
+	return ent.Contains(geometry.TYPE.Point) &&
 		(ent.Contains(sprite.TYPE) || ent.Contains(ui.TYPE.Text) || ent.Contains(shapes.TYPE.Box) ||
 			ent.Contains(shapes.TYPE.SolidBox) || ent.Contains(ui.TYPE.FlatButton) ||
 			ent.Contains(ui.TYPE.ProgressBar) || ent.Contains(shapes.TYPE.Line))
@@ -310,59 +665,261 @@ func (rdm renderingManager) sortRenderable(first, second *goecs.Entity) bool {
 			secondDepth = effects.Get.Layer(second).Depth
 		}
 		if firstDepth == secondDepth {
-			return first.ID() < second.ID()
+			return rdm.sortSameDepth(first, second)
 		}
 		return firstDepth > secondDepth
 	}
 }
 
+// sortSameDepth sort entities in the same effects.Layer, entities without effects.YSort go first by ID, then the
+// ones with effects.YSort by their Y plus offset
+func (rdm renderingManager) sortSameDepth(first, second *goecs.Entity) bool {
+	firstSorted := first.Contains(effects.TYPE.YSort)
+	secondSorted := second.Contains(effects.TYPE.YSort)
+	if firstSorted && secondSorted {
+		firstY := geometry.Get.Point(first).Y + effects.Get.YSort(first).Offset
+		secondY := geometry.Get.Point(second).Y + effects.Get.YSort(second).Offset
+		if firstY != secondY {
+			return firstY < secondY
+		}
+	} else if firstSorted != secondSorted {
+		return secondSorted
+	}
+	return first.ID() < second.ID()
+}
+
+// beginScissor starts drawing only inside an area, that will be intersected with the area that we are using
+func (rdm renderingManager) beginScissor(from geometry.Point, size geometry.Size) {
+	area := geometry.Rect{From: from, Size: size}
+	if l := len(rdm.scissors.areas); l > 0 {
+		area = rdm.scissors.areas[l-1].Intersection(area)
+	}
+	rdm.scissors.areas = append(rdm.scissors.areas, area)
+	rdm.dm.BeginScissor(area.From, area.Size)
+}
+
+// endScissor ends drawing inside the last area, going back to the previous one if we have any
+func (rdm renderingManager) endScissor() {
+	l := len(rdm.scissors.areas)
+	if l == 0 {
+		return
+	}
+	rdm.scissors.areas = rdm.scissors.areas[:l-1]
+	if l > 1 {
+		area := rdm.scissors.areas[l-2]
+		rdm.dm.BeginScissor(area.From, area.Size)
+	} else {
+		rdm.dm.EndScissor()
+	}
+}
+
+// getClipAreas returns the clipArea for each effects.Clip by entity and by group
+func (rdm renderingManager) getClipAreas(world *goecs.World) (byID map[goecs.EntityID]clipArea,
+	byGroup map[string]clipArea) {
+	byID = make(map[goecs.EntityID]clipArea)
+	byGroup = make(map[string]clipArea)
+	for it := world.Iterator(effects.TYPE.Clip, geometry.TYPE.Point); it != nil; it = it.Next() {
+		ent := it.Value()
+		clip := effects.Get.Clip(ent)
+		area := clipArea{
+			rect: geometry.Rect{From: geometry.Get.Point(ent), Size: clip.Size},
+		}
+		if ent.Contains(effects.TYPE.Clipped) {
+			area.clipped = effects.Get.Clipped(ent)
+			area.nested = true
+		}
+		byID[ent.ID()] = area
+		if clip.Group != "" {
+			byGroup[clip.Group] = area
+		}
+	}
+	return
+}
+
+// getClipRect returns the screen area for an effects.Clipped, intersecting the nested effects.Clip
+func (rdm renderingManager) getClipRect(clipped effects.Clipped, byID map[goecs.EntityID]clipArea,
+	byGroup map[string]clipArea) (rect geometry.Rect, ok bool) {
+	for nesting := 0; nesting < maxClipNesting; nesting++ {
+		var area clipArea
+		var found bool
+		if clipped.Parent != 0 {
+			area, found = byID[clipped.Parent]
+		} else {
+			area, found = byGroup[clipped.Group]
+		}
+		if !found {
+			return
+		}
+		if ok {
+			rect = rect.Intersection(area.rect)
+		} else {
+			rect = area.rect
+			ok = true
+		}
+		if !area.nested {
+			return
+		}
+		clipped = area.clipped
+	}
+	return
+}
+
+// setBlendMode changes the current effects.BlendMode only if is different that the one that we are using
+func (rdm renderingManager) setBlendMode(current, mode effects.BlendMode) effects.BlendMode {
+	if current != mode {
+		if current != effects.AlphaBlend {
+			rdm.dm.EndBlendMode()
+		}
+		if mode != effects.AlphaBlend {
+			rdm.dm.BeginBlendMode(mode)
+		}
+	}
+	return mode
+}
+
+// setCamera begins or ends drawing with the camera effects only if is different that what we are using
+func (rdm renderingManager) setCamera(current, moved bool, offset geometry.Point, zoom float32) bool {
+	if current != moved {
+		if moved {
+			rdm.dm.BeginCamera(offset, zoom)
+		} else {
+			rdm.dm.EndCamera()
+		}
+	}
+	return moved
+}
+
+// cameraMoved returns if an entity is draw moved by the camera effects, the ones in a fixed effects.Layer are not
+func cameraMoved(ent *goecs.Entity) bool {
+	return ent.NotContains(effects.TYPE.Layer) || !effects.Get.Layer(ent).Fixed
+}
+
+// cameraView returns the area of the world that is in the screen when drawing with the camera effects
+func (rdm renderingManager) cameraView(offset geometry.Point, zoom float32) geometry.Rect {
+	screen := rdm.dm.GetScreenSize()
+	if zoom <= 0 {
+		return geometry.Rect{Size: screen}
+	}
+	center := geometry.Point{X: screen.Width / 2, Y: screen.Height / 2}
+	return geometry.Rect{
+		From: geometry.Point{
+			X: center.X - ((center.X + offset.X) / zoom),
+			Y: center.Y - ((center.Y + offset.Y) / zoom),
+		},
+		Size: screen.Scale(1 / zoom),
+	}
+}
+
+// cameraRect returns the screen area of an area of the world when drawing with the camera effects
+func (rdm renderingManager) cameraRect(rect geometry.Rect, offset geometry.Point, zoom float32) geometry.Rect {
+	screen := rdm.dm.GetScreenSize()
+	center := geometry.Point{X: screen.Width / 2, Y: screen.Height / 2}
+	return geometry.Rect{
+		From: geometry.Point{
+			X: ((rect.From.X - center.X) * zoom) + center.X + offset.X,
+			Y: ((rect.From.Y - center.Y) * zoom) + center.Y + offset.Y,
+		},
+		Size: rect.Size.Scale(zoom),
+	}
+}
+
 func (rdm renderingManager) System(world *goecs.World, _ float32) error {
 	// sort by renderable in-place
 	world.Sort(rdm.sortRenderable)
 
+	// consecutive entities with the same effects.BlendMode will be drawn without changing it
+	blendMode := effects.AlphaBlend
+
+	// consecutive entities with the same effects.Clip area will be drawn without changing the scissor
+	clipsByID, clipsByGroup := rdm.getClipAreas(world)
+	var clipRect geometry.Rect
+	clipping := false
+
+	// consecutive entities moved by the camera effects will be drawn without changing the camera
+	offset, zoom := rdm.cem.View()
+	cameraActive := offset != geometry.Point{} || zoom != 1
+	camera := false
+
+	defer func() {
+		rdm.setBlendMode(blendMode, effects.AlphaBlend)
+		if clipping {
+			rdm.endScissor()
+		}
+		rdm.setCamera(camera, false, offset, zoom)
+	}()
+
 	// go trough all the world
 	for it := world.Iterator(); it != nil; it = it.Next() {
 		v := it.Value()
 		if !rdm.isRenderable(v) {
 			break // since is sorted by renderable we don't have nothing more to render
 		}
-		if v.Contains(sprite.TYPE) {
-			if err := rdm.renderSprite(v); err != nil {
-				return err
-			}
-		} else if v.Contains(ui.TYPE.FlatButton) {
-			if err := rdm.renderFlatButton(v); err != nil {
-				return err
-			}
-		} else if v.Contains(ui.TYPE.ProgressBar) {
-			if err := rdm.renderProgressBar(v); err != nil {
-				return err
-			}
-		} else if v.Contains(shapes.TYPE.Box) {
-			if err := rdm.renderBox(v); err != nil {
-				return err
-			}
-		} else if v.Contains(shapes.TYPE.SolidBox) {
-			if err := rdm.renderSolidBox(v); err != nil {
-				return err
+		if v.Contains(effects.TYPE.Blend) {
+			blendMode = rdm.setBlendMode(blendMode, effects.Get.Blend(v).Mode)
+		} else {
+			blendMode = rdm.setBlendMode(blendMode, effects.AlphaBlend)
+		}
+		if cameraActive {
+			camera = rdm.setCamera(camera, cameraMoved(v), offset, zoom)
+		}
+		if useScissor {
+			var rect geometry.Rect
+			var clipped bool
+			if v.Contains(effects.TYPE.Clipped) {
+				rect, clipped = rdm.getClipRect(effects.Get.Clipped(v), clipsByID, clipsByGroup)
 			}
-		} else if v.Contains(ui.TYPE.Text, color.TYPE.Solid) {
-			if err := rdm.renderText(v); err != nil {
-				return err
+			// the clip areas move with the entities that are draw with the camera effects
+			if clipped && camera {
+				rect = rdm.cameraRect(rect, offset, zoom)
 			}
-		} else if v.Contains(shapes.TYPE.Line) {
-			if err := rdm.renderLine(v); err != nil {
-				return err
+			if clipping != clipped || rect != clipRect {
+				if clipping {
+					rdm.endScissor()
+				}
+				if clipped {
+					rdm.beginScissor(rect.From, rect.Size)
+				}
+				clipping, clipRect = clipped, rect
 			}
 		}
+		if err := rdm.renderEntity(world, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderEntity draws an entity depending on its components
+func (rdm renderingManager) renderEntity(world *goecs.World, v *goecs.Entity) error {
+	// the color effects only change the colors that we draw, not the entity ones
+	paint := effectColors(v)
+	switch {
+	case v.Contains(tiled.TYPE.Chunk):
+		return rdm.renderTileChunk(world, v, paint)
+	case v.Contains(sprite.TYPE):
+		return rdm.renderSprite(v, paint)
+	case v.Contains(ui.TYPE.FlatButton):
+		return rdm.renderFlatButton(v, paint)
+	case v.Contains(ui.TYPE.ProgressBar):
+		return rdm.renderProgressBar(v, paint)
+	case v.Contains(shapes.TYPE.Box):
+		return rdm.renderBox(v, paint)
+	case v.Contains(shapes.TYPE.SolidBox):
+		return rdm.renderSolidBox(v, paint)
+	case v.Contains(ui.TYPE.Text, color.TYPE.Solid):
+		return rdm.renderText(v, paint)
+	case v.Contains(shapes.TYPE.Line):
+		return rdm.renderLine(v, paint)
 	}
 	return nil
 }
 
 // Rendering returns a managers.WithSystem that will handle rendering
-func Rendering(dm DeviceManager, sm *StorageManager) WithSystem {
+func Rendering(dm DeviceManager, sm *StorageManager, cem CameraEffecter) WithSystem {
 	return &renderingManager{
-		dm: dm,
-		sm: sm,
+		dm:       dm,
+		sm:       sm,
+		cem:      cem,
+		scissors: &scissorStack{},
 	}
 }
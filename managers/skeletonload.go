@@ -0,0 +1,271 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/skeleton"
+	"io/ioutil"
+	"math"
+	"sort"
+)
+
+const (
+	spineStepped = "stepped" // spineStepped is the Spine curve for keys without interpolation
+	spineRegion  = "region"  // spineRegion is the Spine attachment type for an image
+	spineDefault = "default" // spineDefault is the name of the Spine default skin
+)
+
+// spineData is the subset of the Spine JSON that we support: bones, slots with region attachments in the default
+// skin, and rotate, translate and scale timelines
+type spineData struct {
+	Bones []struct {
+		Name     string   `json:"name"`
+		Parent   string   `json:"parent"`
+		X        float32  `json:"x"`
+		Y        float32  `json:"y"`
+		Rotation float32  `json:"rotation"`
+		ScaleX   *float32 `json:"scaleX"`
+		ScaleY   *float32 `json:"scaleY"`
+	} `json:"bones"`
+	Slots []struct {
+		Name       string `json:"name"`
+		Bone       string `json:"bone"`
+		Attachment string `json:"attachment"`
+	} `json:"slots"`
+	Skins      json.RawMessage `json:"skins"`
+	Animations map[string]struct {
+		Bones map[string]map[string][]spineKey `json:"bones"`
+	} `json:"animations"`
+}
+
+// spineAttachment is a Spine region attachment
+type spineAttachment struct {
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	Path     string   `json:"path"`
+	X        float32  `json:"x"`
+	Y        float32  `json:"y"`
+	Rotation float32  `json:"rotation"`
+	ScaleX   *float32 `json:"scaleX"`
+	ScaleY   *float32 `json:"scaleY"`
+}
+
+// spineSkin are the attachments by slot and by name of a Spine skin
+type spineSkin map[string]map[string]spineAttachment
+
+// spineKey is a key in a Spine timeline, the rotation is angle in older versions and value in newer
+type spineKey struct {
+	Time  float32         `json:"time"`
+	Angle *float32        `json:"angle"`
+	Value *float32        `json:"value"`
+	X     *float32        `json:"x"`
+	Y     *float32        `json:"y"`
+	Curve json.RawMessage `json:"curve"`
+}
+
+// spineScale returns an uniform scale from the Spine scales in each axis, that are 1 when not set
+func spineScale(x, y *float32) float32 {
+	sx, sy := float32(1), float32(1)
+	if x != nil {
+		sx = *x
+	}
+	if y != nil {
+		sy = *y
+	}
+	return float32(math.Abs(float64(sx))+math.Abs(float64(sy))) / 2
+}
+
+// spineDefaultSkin returns the default skin in Spine skins, that are a map in older versions and an array in newer
+func spineDefaultSkin(raw json.RawMessage) (skin spineSkin, err error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return spineSkin{}, nil
+	}
+	if raw[0] == '{' {
+		skins := make(map[string]spineSkin)
+		if err = json.Unmarshal(raw, &skins); err == nil {
+			skin = skins[spineDefault]
+		}
+		return
+	}
+	var skins []struct {
+		Name        string    `json:"name"`
+		Attachments spineSkin `json:"attachments"`
+	}
+	if err = json.Unmarshal(raw, &skins); err == nil {
+		for _, s := range skins {
+			if s.Name == spineDefault {
+				skin = s.Attachments
+			}
+		}
+	}
+	return
+}
+
+// spineTimeline returns a skeleton.Timeline from a Spine timeline, converting it to our coordinates that have Y
+// down and clockwise rotations, curves other than stepped are interpolated linearly
+func spineTimeline(bone, kind string, keys []spineKey) (tl skeleton.Timeline, ok bool) {
+	tl = skeleton.Timeline{Bone: bone}
+	switch kind {
+	case "rotate":
+		tl.Property = skeleton.Rotate
+	case "translate":
+		tl.Property = skeleton.Translate
+	case "scale":
+		tl.Property = skeleton.Scale
+	default:
+		return tl, false
+	}
+
+	for _, k := range keys {
+		key := skeleton.Key{Time: k.Time}
+		var curve string
+		key.Stepped = json.Unmarshal(k.Curve, &curve) == nil && curve == spineStepped
+		switch tl.Property {
+		case skeleton.Rotate:
+			if k.Angle != nil {
+				key.Value.X = -*k.Angle
+			} else if k.Value != nil {
+				key.Value.X = -*k.Value
+			}
+		case skeleton.Translate:
+			key.Value = geometry.Point{X: valueOr(k.X, 0), Y: -valueOr(k.Y, 0)}
+		case skeleton.Scale:
+			key.Value.X = spineScale(k.X, k.Y)
+		}
+		tl.Keys = append(tl.Keys, key)
+	}
+	sort.SliceStable(tl.Keys, func(i, j int) bool {
+		return tl.Keys[i].Time < tl.Keys[j].Time
+	})
+	return tl, len(tl.Keys) > 0
+}
+
+// valueOr returns a value or a default if is not set
+func valueOr(value *float32, def float32) float32 {
+	if value == nil {
+		return def
+	}
+	return *value
+}
+
+// LoadSkeleton preloads a skeleton.Skeleton from a Spine JSON, which parts use sprites in a preloaded sheet
+func (sm *StorageManager) LoadSkeleton(name string, sheet string) (err error) {
+	var content []byte
+	if content, err = ioutil.ReadFile(name); err != nil {
+		return
+	}
+	data := spineData{}
+	if err = json.Unmarshal(content, &data); err != nil {
+		return
+	}
+	var skin spineSkin
+	if skin, err = spineDefaultSkin(data.Skins); err != nil {
+		return
+	}
+
+	skl := skeleton.Skeleton{
+		Sheet: sheet,
+		Clips: make(map[string]skeleton.Clip),
+		Speed: 1,
+		Scale: 1,
+	}
+
+	for _, b := range data.Bones {
+		skl.Bones = append(skl.Bones, skeleton.Bone{
+			Name:   b.Name,
+			Parent: b.Parent,
+			Local: skeleton.Transform{
+				Position: geometry.Point{X: b.X, Y: -b.Y},
+				Rotation: -b.Rotation,
+				Scale:    spineScale(b.ScaleX, b.ScaleY),
+			},
+		})
+	}
+
+	for _, slot := range data.Slots {
+		if slot.Attachment == "" {
+			continue
+		}
+		att, ok := skin[slot.Name][slot.Attachment]
+		if !ok || (att.Type != "" && att.Type != spineRegion) {
+			continue
+		}
+		spriteName := slot.Attachment
+		if att.Path != "" {
+			spriteName = att.Path
+		} else if att.Name != "" {
+			spriteName = att.Name
+		}
+		// sheets made from the Spine images may include the extension
+		if _, err := sm.GetSpriteDef(sheet, spriteName); err != nil {
+			if _, err := sm.GetSpriteDef(sheet, spriteName+".png"); err == nil {
+				spriteName += ".png"
+			}
+		}
+		skl.Parts = append(skl.Parts, skeleton.Part{
+			Bone:   slot.Bone,
+			Sprite: spriteName,
+			Local: skeleton.Transform{
+				Position: geometry.Point{X: att.X, Y: -att.Y},
+				Rotation: -att.Rotation,
+				Scale:    spineScale(att.ScaleX, att.ScaleY),
+			},
+		})
+	}
+
+	for clipName, anim := range data.Animations {
+		clip := skeleton.Clip{}
+		for bone, timelines := range anim.Bones {
+			for kind, keys := range timelines {
+				if tl, ok := spineTimeline(bone, kind, keys); ok {
+					clip.Timelines = append(clip.Timelines, tl)
+					if last := tl.Keys[len(tl.Keys)-1].Time; last > clip.Duration {
+						clip.Duration = last
+					}
+				}
+			}
+		}
+		skl.Clips[clipName] = clip
+	}
+
+	sm.skeletons[name] = skl
+	return
+}
+
+// GetSkeleton returns a preloaded skeleton.Skeleton
+func (sm StorageManager) GetSkeleton(name string) (skeleton.Skeleton, error) {
+	if skl, ok := sm.skeletons[name]; ok {
+		clips := make(map[string]skeleton.Clip, len(skl.Clips))
+		for k, v := range skl.Clips {
+			clips[k] = v
+		}
+		skl.Clips = clips
+		return skl, nil
+	}
+	return skeleton.Skeleton{}, fmt.Errorf("can not find skeleton %q", name)
+}
@@ -0,0 +1,282 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"fmt"
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/skeleton"
+	"github.com/juan-medina/gosge/components/sprite"
+	"github.com/juan-medina/gosge/events"
+	"math"
+)
+
+type skeletonManager struct{}
+
+// boneTransform is the transform of a bone relative to the skeleton entity
+type boneTransform struct {
+	position geometry.Point
+	rotation float32
+	scale    float32
+}
+
+func (sk skeletonManager) System(world *goecs.World, delta float32) error {
+	for it := world.Iterator(skeleton.TYPE.Skeleton, geometry.TYPE.Point); it != nil; it = it.Next() {
+		ent := it.Value()
+		skl := skeleton.Get.Skeleton(ent)
+
+		depth := DefaultLayer
+		if ent.Contains(effects.TYPE.Layer) {
+			depth = effects.Get.Layer(ent).Depth
+		}
+
+		// init the state or get it from entity
+		var state skeleton.State
+		if ent.NotContains(skeleton.TYPE.State) {
+			state = skeleton.State{
+				Current: skl.Current,
+				Parts:   sk.addParts(world, ent.ID(), skl, depth),
+			}
+		} else {
+			state = skeleton.Get.State(ent)
+		}
+
+		if state.Current != skl.Current {
+			state.Current = skl.Current
+			state.Time = 0
+			state.Ended = false
+		}
+
+		var clip skeleton.Clip
+		if state.Current != "" {
+			var ok bool
+			if clip, ok = skl.Clips[state.Current]; !ok {
+				return fmt.Errorf("can not find skeleton clip: %q", state.Current)
+			}
+			sk.advance(world, ent.ID(), &state, clip, delta*skl.Speed)
+		}
+
+		sk.updateParts(world, ent, skl, state, sk.pose(skl, clip, state.Time), depth)
+		ent.Set(state)
+	}
+
+	return sk.removeOrphans(world)
+}
+
+// addParts adds an entity per each skeleton.Part of a skeleton.Skeleton, in draw order
+func (sk skeletonManager) addParts(world *goecs.World, id goecs.EntityID, skl skeleton.Skeleton,
+	depth float32) []goecs.EntityID {
+	parts := make([]goecs.EntityID, len(skl.Parts))
+	for i, part := range skl.Parts {
+		parts[i] = world.AddEntity(
+			sprite.Sprite{
+				Sheet: skl.Sheet,
+				Name:  part.Sprite,
+				Scale: skl.Scale,
+			},
+			skeleton.PartInfo{
+				Skeleton: id,
+				Bone:     part.Bone,
+				Sprite:   part.Sprite,
+			},
+			geometry.Point{},
+			effects.Layer{Depth: depth},
+		)
+	}
+	return parts
+}
+
+// advance moves the time of a skeleton.State in a skeleton.Clip, notifying when the clip reach its end
+func (sk skeletonManager) advance(world *goecs.World, id goecs.EntityID, state *skeleton.State, clip skeleton.Clip,
+	delta float32) {
+	if state.Ended {
+		return
+	}
+	if state.Time += delta; clip.Duration <= 0 || state.Time < clip.Duration {
+		return
+	}
+	if clip.Once {
+		state.Time = clip.Duration
+		state.Ended = true
+	} else {
+		state.Time = float32(math.Mod(float64(state.Time), float64(clip.Duration)))
+	}
+	world.Signal(events.AnimationFinishedEvent{Entity: id, Sequence: state.Current})
+}
+
+// pose returns the transform of each bone of a skeleton.Skeleton relative to its entity, in a time of a
+// skeleton.Clip
+func (sk skeletonManager) pose(skl skeleton.Skeleton, clip skeleton.Clip, time float32) map[string]boneTransform {
+	locals := make(map[string]skeleton.Transform, len(skl.Bones))
+	for _, bone := range skl.Bones {
+		local := bone.Local
+		local.Scale = scaleOrOne(local.Scale)
+		locals[bone.Name] = local
+	}
+
+	for _, tl := range clip.Timelines {
+		local, ok := locals[tl.Bone]
+		if !ok || len(tl.Keys) == 0 {
+			continue
+		}
+		value := sampleKeys(tl.Keys, time)
+		switch tl.Property {
+		case skeleton.Rotate:
+			local.Rotation += value.X
+		case skeleton.Translate:
+			local.Position = local.Position.Add(value)
+		case skeleton.Scale:
+			local.Scale *= value.X
+		}
+		locals[tl.Bone] = local
+	}
+
+	root := boneTransform{scale: scaleOrOne(skl.Scale)}
+	bones := make(map[string]boneTransform, len(skl.Bones))
+	for _, bone := range skl.Bones {
+		parent, ok := bones[bone.Parent]
+		if !ok {
+			parent = root
+		}
+		bones[bone.Name] = composeBone(parent, locals[bone.Name])
+	}
+	return bones
+}
+
+// updateParts places the entities of the parts of a skeleton.Skeleton for a pose
+func (sk skeletonManager) updateParts(world *goecs.World, ent *goecs.Entity, skl skeleton.Skeleton,
+	state skeleton.State, bones map[string]boneTransform, depth float32) {
+	pos := geometry.Get.Point(ent)
+	hide := ent.Contains(effects.TYPE.Hide)
+	for i, id := range state.Parts {
+		partEnt := world.Get(id)
+		if i >= len(skl.Parts) || partEnt == nil || partEnt.ID() != id {
+			continue
+		}
+		part := skl.Parts[i]
+		bone, ok := bones[part.Bone]
+		if !ok {
+			continue
+		}
+		transform := composeBone(bone, part.Local)
+
+		// flip around the skeleton entity position
+		if skl.FlipX {
+			transform.position.X = -transform.position.X
+		}
+		if skl.FlipY {
+			transform.position.Y = -transform.position.Y
+		}
+		if skl.FlipX != skl.FlipY {
+			transform.rotation = -transform.rotation
+		}
+
+		partEnt.Set(pos.Add(transform.position))
+		partEnt.Set(sprite.Sprite{
+			Sheet:    skl.Sheet,
+			Name:     part.Sprite,
+			Rotation: transform.rotation,
+			Scale:    transform.scale,
+			FlipX:    skl.FlipX,
+			FlipY:    skl.FlipY,
+		})
+		partEnt.Set(effects.Layer{Depth: depth})
+		if hide {
+			partEnt.Set(effects.Hide{})
+		} else if partEnt.Contains(effects.TYPE.Hide) {
+			partEnt.Remove(effects.TYPE.Hide)
+		}
+	}
+}
+
+// removeOrphans removes the parts entities which skeleton has been removed
+func (sk skeletonManager) removeOrphans(world *goecs.World) error {
+	orphans := make([]goecs.EntityID, 0)
+	for it := world.Iterator(skeleton.TYPE.PartInfo); it != nil; it = it.Next() {
+		ent := it.Value()
+		info := skeleton.Get.PartInfo(ent)
+		if owner := world.Get(info.Skeleton); owner == nil || owner.ID() != info.Skeleton ||
+			owner.NotContains(skeleton.TYPE.Skeleton) {
+			orphans = append(orphans, ent.ID())
+		}
+	}
+	for _, id := range orphans {
+		if err := world.Remove(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// composeBone returns the transform of a child from its parent transform and its local skeleton.Transform
+func composeBone(parent boneTransform, local skeleton.Transform) boneTransform {
+	rad := float64(parent.rotation) * math.Pi / 180
+	sin, cos := float32(math.Sin(rad)), float32(math.Cos(rad))
+	x := local.Position.X * parent.scale
+	y := local.Position.Y * parent.scale
+	return boneTransform{
+		position: geometry.Point{
+			X: parent.position.X + (x * cos) - (y * sin),
+			Y: parent.position.Y + (x * sin) + (y * cos),
+		},
+		rotation: parent.rotation + local.Rotation,
+		scale:    parent.scale * scaleOrOne(local.Scale),
+	}
+}
+
+// sampleKeys returns the value of sorted skeleton.Key in a given time
+func sampleKeys(keys []skeleton.Key, time float32) geometry.Point {
+	if time <= keys[0].Time {
+		return keys[0].Value
+	}
+	for i := 0; i < len(keys)-1; i++ {
+		from, to := keys[i], keys[i+1]
+		if time >= to.Time {
+			continue
+		}
+		if from.Stepped || to.Time <= from.Time {
+			return from.Value
+		}
+		t := from.Ease.Apply((time - from.Time) / (to.Time - from.Time))
+		return geometry.Point{
+			X: from.Value.X + ((to.Value.X - from.Value.X) * t),
+			Y: from.Value.Y + ((to.Value.Y - from.Value.Y) * t),
+		}
+	}
+	return keys[len(keys)-1].Value
+}
+
+// scaleOrOne returns a scale, or 1 if is 0
+func scaleOrOne(scale float32) float32 {
+	if scale == 0 {
+		return 1
+	}
+	return scale
+}
+
+// Skeletons returns a managers.WithSystem for handling skeleton.Skeleton
+func Skeletons() WithSystem {
+	return &skeletonManager{}
+}
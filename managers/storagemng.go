@@ -26,13 +26,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/animation"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/cutscene"
 	"github.com/juan-medina/gosge/components/geometry"
-	"github.com/lafriks/go-tiled"
+	"github.com/juan-medina/gosge/components/skeleton"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
-	"strconv"
 )
 
 type spriteSheetData struct {
@@ -64,6 +66,13 @@ type StorageManager struct {
 	musics    map[string]components.MusicDef
 	sounds    map[string]components.SoundDef
 	tiledMaps map[string]components.TiledMapDef
+	ldtks     map[string]components.LDtkProjectDef
+	sequences map[string]map[string]animation.Sequence
+	machines  map[string]animation.StateMachine
+	skeletons map[string]skeleton.Skeleton
+	timelines map[string]cutscene.Timeline
+	locales   map[string]components.LocaleDef
+	language  string
 	dm        DeviceManager
 }
 
@@ -86,84 +95,157 @@ func (sm *StorageManager) GetTiledMapDef(name string) (components.TiledMapDef, e
 	return components.TiledMapDef{}, fmt.Errorf("can not find tiled map %q", name)
 }
 
+// LoadLDtkProject preload a LDtk project
+func (sm *StorageManager) LoadLDtkProject(name string) (err error) {
+	var project components.LDtkProjectDef
+	if _, ok := sm.ldtks[name]; !ok {
+		if project, err = sm.loadLDtkProject(name); err == nil {
+			sm.ldtks[name] = project
+		}
+	}
+	return
+}
+
+// GetLDtkProjectDef returns the components.LDtkProjectDef for a LDtk project
+func (sm *StorageManager) GetLDtkProjectDef(name string) (components.LDtkProjectDef, error) {
+	if project, ok := sm.ldtks[name]; ok {
+		return project, nil
+	}
+	return components.LDtkProjectDef{}, fmt.Errorf("can not find LDtk project %q", name)
+}
+
+// GetLDtkLevelDef returns the components.LDtkLevelDef for a level in a LDtk project
+func (sm *StorageManager) GetLDtkLevelDef(project, level string) (def components.LDtkLevelDef, err error) {
+	var projectDef components.LDtkProjectDef
+	if projectDef, err = sm.GetLDtkProjectDef(project); err == nil {
+		var ok bool
+		if def, ok = projectDef.Levels[level]; !ok {
+			err = fmt.Errorf("can not find level %q in LDtk project %q", level, project)
+		}
+	}
+	return
+}
+
 func (sm StorageManager) loadTileMap(name string) (result components.TiledMapDef, err error) {
-	var tiledMap *tiled.Map
-	if tiledMap, err = tiled.LoadFromFile(name); err == nil {
+	var file tiledMapFile
+	if file, err = readTiledMap(name); err == nil {
+		tiledMap := file.data
+		result.StaggerAxis = file.staggerAxis
+		result.StaggerIndex = file.staggerIndex
+		result.Infinite = file.infinite
+		result.OriginCol = file.chunks.originCol
+		result.OriginRow = file.chunks.originRow
+		result.ChunkWidth = file.chunks.chunkWidth
+		result.ChunkHeight = file.chunks.chunkHeight
 
 		result.Properties = make(map[uint32]map[string]string, 0)
+		result.Sprites = make(map[uint32]components.SpriteDef, 0)
+		result.Animations = make(map[uint32][]components.TileAnimationFrame, 0)
 		result.Cols = int32(tiledMap.Width)
 		result.Rows = int32(tiledMap.Height)
 		result.TileSize = geometry.Size{
 			Width:  float32(tiledMap.TileWidth),
 			Height: float32(tiledMap.TileHeight),
 		}
-		result.Size = geometry.Size{
-			Width:  float32(result.Cols) * result.TileSize.Width,
-			Height: float32(result.Rows) * result.TileSize.Height,
-		}
-
 		result.Data = tiledMap
-		dir := filepath.Dir(name)
-		var texture components.TextureDef
-		for _, ts := range tiledMap.Tilesets {
-			texturePath := path.Join(dir, ts.Image.Source)
-			if texture, err = sm.dm.LoadTexture(texturePath); err != nil {
+		result.Size = tiledMapSize(result)
+		if tiledMap.BackgroundColor != "" {
+			if result.Background, err = parseTiledColor(tiledMap.BackgroundColor); err != nil {
 				return
 			}
-			st := make(spriteSheet, 0)
-			sm.sheets[name] = st
-
-			tilesetTileCount := ts.TileCount
-			tilesetColumns := ts.Columns
-			margin := ts.Margin
-			spacing := ts.Spacing
-
-			if tilesetColumns == 0 {
-				tilesetColumns = ts.Image.Width / (ts.TileWidth + spacing)
-			}
-			if tilesetTileCount == 0 {
-				tilesetTileCount = (ts.Image.Height / (ts.TileHeight + spacing)) * tilesetColumns
-			}
-
-			for i := ts.FirstGID; i < ts.FirstGID+uint32(tilesetTileCount); i++ {
-				x := int(i-ts.FirstGID) % tilesetColumns
-				y := int(i-ts.FirstGID) / tilesetColumns
-				xOffset := x*spacing + margin
-				yOffset := y*spacing + margin
-				origin := geometry.Rect{
-					From: geometry.Point{
-						X: float32(x*ts.TileWidth + xOffset),
-						Y: float32(y*ts.TileHeight + yOffset),
-					},
-					Size: geometry.Size{
-						Width:  float32(ts.TileWidth),
-						Height: float32(ts.TileHeight),
-					},
+		}
+		if result.Layers, err = flattenTiledLayers(tiledMap, file.root.Children, tiledLayers{
+			tiles:   tiledMap.Layers,
+			objects: tiledMap.ObjectGroups,
+			images:  tiledMap.ImageLayers,
+			groups:  tiledMap.Groups,
+		}, components.TiledLayerDef{
+			Visible:  true,
+			Opacity:  1,
+			Tint:     color.White,
+			Parallax: geometry.Point{X: 1, Y: 1},
+		}); err != nil {
+			return
+		}
+		if file.infinite {
+			// the chunks are in the same order than the tile layers
+			tiles := 0
+			for i, layer := range result.Layers {
+				if layer.Kind != components.TiledTileLayer || tiles >= len(file.chunks.layers) {
+					continue
 				}
-				id := i - 1
-				sprName := strconv.Itoa(int(id))
-				st[sprName] = components.SpriteDef{
-					Texture: texture,
-					Origin:  origin,
-					Pivot: geometry.Point{
-						X: 0,
-						Y: 0,
-					},
+				if result.Layers[i].Chunks, err = infiniteChunks(tiledMap, file.chunks.layers[tiles]); err != nil {
+					return
 				}
+				tiles++
 			}
-			// cache block properties
+		}
+		st := make(spriteSheet, 0)
+		sm.sheets[name] = st
+		if err = sm.loadImageLayers(tiledMap, result.Layers, st); err != nil {
+			return
+		}
+		for _, ts := range tiledMap.Tilesets {
+			if err = sm.loadTileset(tiledMap, ts, st, result.Sprites); err != nil {
+				return
+			}
+			// cache block properties by global id
 			for _, t := range ts.Tiles {
 				properties := make(map[string]string, 0)
 				for _, p := range t.Properties {
 					properties[p.Name] = p.Value
 				}
-				result.Properties[t.ID] = properties
+				result.Properties[ts.FirstGID+t.ID] = properties
+
+				// cache the animation frames
+				if len(t.Animation) > 0 {
+					frames := make([]components.TileAnimationFrame, len(t.Animation))
+					for i, f := range t.Animation {
+						frames[i] = components.TileAnimationFrame{
+							GID:      ts.FirstGID + f.TileID,
+							Duration: float32(f.Duration) / 1000,
+						}
+					}
+					result.Animations[ts.FirstGID+t.ID] = frames
+				}
+			}
+		}
+	}
+	return
+}
+
+// LoadLocale preloads a locale from a JSON or PO file
+func (sm *StorageManager) LoadLocale(name string) (err error) {
+	var locale components.LocaleDef
+	if locale, err = loadLocale(name); err == nil {
+		if current, ok := sm.locales[locale.Language]; ok {
+			// merge with the texts that we already have for this language
+			for k, v := range locale.Texts {
+				current.Texts[k] = v
+			}
+		} else {
+			if len(sm.locales) == 0 {
+				sm.language = locale.Language
 			}
+			sm.locales[locale.Language] = locale
 		}
 	}
 	return
 }
 
+//GetLocaleDef returns the components.LocaleDef for a language
+func (sm *StorageManager) GetLocaleDef(language string) (components.LocaleDef, error) {
+	if _, ok := sm.locales[language]; ok {
+		return sm.locales[language], nil
+	}
+	return components.LocaleDef{}, fmt.Errorf("can not find locale %q", language)
+}
+
+// GetDefaultLanguage returns the language of the first locale loaded
+func (sm *StorageManager) GetDefaultLanguage() string {
+	return sm.language
+}
+
 //LoadSound preload a sound wave
 func (sm *StorageManager) LoadSound(name string) (err error) {
 	var sound components.SoundDef
@@ -263,7 +345,7 @@ func (sm *StorageManager) handleSheet(data spriteSheetData, name string) (err er
 	return
 }
 
-// LoadSpriteSheet preloads a sprite.Sprite sheet
+// LoadSpriteSheet preloads a sprite.Sprite sheet, from a TexturePacker or an Aseprite JSON
 func (sm *StorageManager) LoadSpriteSheet(name string) (err error) {
 	data := spriteSheetData{}
 	var jsonFile *os.File
@@ -272,6 +354,9 @@ func (sm *StorageManager) LoadSpriteSheet(name string) (err error) {
 		defer jsonFile.Close()
 		var bytes []byte
 		if bytes, err = ioutil.ReadAll(jsonFile); err == nil {
+			if isAsepriteSheet(bytes) {
+				return sm.handleAsepriteSheet(bytes, name)
+			}
 			if err = json.Unmarshal(bytes, &data); err == nil {
 				return sm.handleSheet(data, name)
 			}
@@ -322,6 +407,72 @@ func (sm StorageManager) GetSpriteSize(sheet string, name string) (geometry.Size
 	return def.Origin.Size, err
 }
 
+// GetAnimation returns the animation.Sequence for a tag in a sprite sheet loaded from an Aseprite JSON or a GIF
+func (sm StorageManager) GetAnimation(sheet string, tag string) (animation.Sequence, error) {
+	if sequences, ok := sm.sequences[sheet]; ok {
+		if seq, ok := sequences[tag]; ok {
+			return seq, nil
+		}
+		return animation.Sequence{}, fmt.Errorf("can not find animation %q in sheet %q", tag, sheet)
+	}
+	return animation.Sequence{}, fmt.Errorf("can not find animations for sprite sheet %q", sheet)
+}
+
+// GetAnimations returns all the animation.Sequence, by tag, in a sprite sheet loaded from an Aseprite JSON or a GIF
+func (sm StorageManager) GetAnimations(sheet string) (map[string]animation.Sequence, error) {
+	if sequences, ok := sm.sequences[sheet]; ok {
+		result := make(map[string]animation.Sequence, len(sequences))
+		for tag, seq := range sequences {
+			result[tag] = seq
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("can not find animations for sprite sheet %q", sheet)
+}
+
+// LoadStateMachine preloads an animation.StateMachine from a JSON file
+func (sm *StorageManager) LoadStateMachine(name string) (err error) {
+	var bytes []byte
+	if bytes, err = ioutil.ReadFile(name); err == nil {
+		machine := animation.StateMachine{}
+		if err = json.Unmarshal(bytes, &machine); err == nil {
+			if _, ok := machine.States[machine.Initial]; !ok {
+				return fmt.Errorf("can not find initial state %q in state machine %q", machine.Initial, name)
+			}
+			sm.machines[name] = machine
+		}
+	}
+	return
+}
+
+// GetStateMachine returns a copy of an animation.StateMachine, with its own parameters
+func (sm StorageManager) GetStateMachine(name string) (animation.StateMachine, error) {
+	if machine, ok := sm.machines[name]; ok {
+		return machine.Clone(), nil
+	}
+	return animation.StateMachine{}, fmt.Errorf("can not find state machine %q", name)
+}
+
+// LoadTimeline preloads a cutscene.Timeline from a JSON file
+func (sm *StorageManager) LoadTimeline(name string) (err error) {
+	var bytes []byte
+	if bytes, err = ioutil.ReadFile(name); err == nil {
+		tl := cutscene.Timeline{}
+		if err = json.Unmarshal(bytes, &tl); err == nil {
+			sm.timelines[name] = tl
+		}
+	}
+	return
+}
+
+// GetTimeline returns a preloaded cutscene.Timeline
+func (sm StorageManager) GetTimeline(name string) (cutscene.Timeline, error) {
+	if tl, ok := sm.timelines[name]; ok {
+		return tl, nil
+	}
+	return cutscene.Timeline{}, fmt.Errorf("can not find timeline %q", name)
+}
+
 //Clear all loaded data
 func (sm *StorageManager) Clear() {
 	sm.sheets = make(map[string]spriteSheet, 0)
@@ -346,6 +497,13 @@ func (sm *StorageManager) Clear() {
 	}
 	sm.sounds = make(map[string]components.SoundDef, 0)
 	sm.tiledMaps = make(map[string]components.TiledMapDef, 0)
+	sm.ldtks = make(map[string]components.LDtkProjectDef, 0)
+	sm.sequences = make(map[string]map[string]animation.Sequence, 0)
+	sm.machines = make(map[string]animation.StateMachine, 0)
+	sm.skeletons = make(map[string]skeleton.Skeleton, 0)
+	sm.timelines = make(map[string]cutscene.Timeline, 0)
+	sm.locales = make(map[string]components.LocaleDef, 0)
+	sm.language = ""
 }
 
 // Storage returns a new managers.StorageManager
@@ -357,6 +515,12 @@ func Storage(dm DeviceManager) *StorageManager {
 		musics:    make(map[string]components.MusicDef, 0),
 		sounds:    make(map[string]components.SoundDef, 0),
 		tiledMaps: make(map[string]components.TiledMapDef, 0),
+		ldtks:     make(map[string]components.LDtkProjectDef, 0),
+		sequences: make(map[string]map[string]animation.Sequence, 0),
+		machines:  make(map[string]animation.StateMachine, 0),
+		skeletons: make(map[string]skeleton.Skeleton, 0),
+		timelines: make(map[string]cutscene.Timeline, 0),
+		locales:   make(map[string]components.LocaleDef, 0),
 		dm:        dm,
 	}
 }
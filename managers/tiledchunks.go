@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/tiled"
+	"math"
+)
+
+// chunkedLayer returns if a tile layer is draw in tiled.Chunk, layers that are sorted with effects.YSort still need
+// an entity per tile
+func (tm tiledManager) chunkedLayer(tiledMap tiled.Map, layer components.TiledLayerDef) bool {
+	return tiledMap.ChunkSize > 0 && !layer.Tiles.Properties.GetBool(ySortProperty)
+}
+
+// cellChunk is an area of cells of a map
+type cellChunk struct {
+	col    int           // col is the first col of the chunk
+	row    int           // row is the first row of the chunk
+	cells  []tileCell    // cells are the chunk cells in render order
+	bounds geometry.Rect // bounds are the area, in map pixels, where the chunk tiles could be draw
+}
+
+// chunkCells returns the cellChunk of a given size in cells for a map, chunks are in the order of their first cell
+// to be draw so they overlap as the tiles will do
+func chunkCells(mapDef components.TiledMapDef, cells []tileCell, size int) []cellChunk {
+	chunksCols := (mapDef.Data.Width + size - 1) / size
+	index := make(map[int]int)
+	chunks := make([]cellChunk, 0)
+	for _, cell := range cells {
+		key := ((cell.row / size) * chunksCols) + (cell.col / size)
+		i, ok := index[key]
+		if !ok {
+			i = len(chunks)
+			index[key] = i
+			chunks = append(chunks, cellChunk{
+				col:   (cell.col / size) * size,
+				row:   (cell.row / size) * size,
+				cells: make([]tileCell, 0, size*size),
+			})
+		}
+		chunks[i].cells = append(chunks[i].cells, cell)
+	}
+
+	overhang, offset := tileOverhang(mapDef)
+	for i := range chunks {
+		chunks[i].bounds = chunkBounds(mapDef, chunks[i].cells, overhang, offset)
+	}
+	return chunks
+}
+
+// addChunk adds the tiled.Chunk entity for a cellChunk of a tile layer
+func (tm tiledManager) addChunk(world *goecs.World, mapID goecs.EntityID, mapDef components.TiledMapDef,
+	layer int, depth float32, chunk cellChunk) goecs.EntityID {
+	cells := make([]int, len(chunk.cells))
+	for i, cell := range chunk.cells {
+		cells[i] = (cell.row * mapDef.Data.Width) + cell.col
+	}
+	id := world.AddEntity(
+		tiled.Chunk{
+			Map:    mapID,
+			Layer:  layer,
+			Col:    chunk.col,
+			Row:    chunk.row,
+			Cells:  cells,
+			Bounds: chunk.bounds,
+		},
+		effects.Layer{Depth: depth},
+	)
+	if tint, ok := layerTint(mapDef.Layers[layer]); ok {
+		world.Get(id).Add(tint)
+	}
+	return id
+}
+
+// tileOverhang returns how much, in map pixels, the tiles of a map could be bigger than a cell, and how much could
+// be moved by their tileset offset
+func tileOverhang(mapDef components.TiledMapDef) (overhang geometry.Size, offset geometry.Point) {
+	grow := func(width, height int) {
+		overhang.Width = float32(math.Max(float64(overhang.Width), float64(width)-float64(mapDef.TileSize.Width)))
+		overhang.Height = float32(math.Max(float64(overhang.Height),
+			float64(height)-float64(mapDef.TileSize.Height)))
+	}
+	for _, ts := range mapDef.Data.Tilesets {
+		grow(ts.TileWidth, ts.TileHeight)
+		for _, t := range ts.Tiles {
+			if t.Image != nil {
+				grow(t.Image.Width, t.Image.Height)
+			}
+		}
+		if ts.TileOffset != nil {
+			offset.X = float32(math.Max(float64(offset.X), math.Abs(float64(ts.TileOffset.X))))
+			offset.Y = float32(math.Max(float64(offset.Y), math.Abs(float64(ts.TileOffset.Y))))
+		}
+	}
+	return
+}
+
+// chunkBounds returns the area, in map pixels, where the tiles of some cells could be draw, tiles are aligned to
+// the bottom left of its cell so bigger tiles overhang to the top and the right
+func chunkBounds(mapDef components.TiledMapDef, cells []tileCell, overhang geometry.Size,
+	offset geometry.Point) geometry.Rect {
+	var from, to geometry.Point
+	for i, c := range cells {
+		cell := tileToMap(mapDef, c.col, c.row)
+		if i == 0 {
+			from, to = cell, cell
+			continue
+		}
+		from.X = float32(math.Min(float64(from.X), float64(cell.X)))
+		from.Y = float32(math.Min(float64(from.Y), float64(cell.Y)))
+		to.X = float32(math.Max(float64(to.X), float64(cell.X)))
+		to.Y = float32(math.Max(float64(to.Y), float64(cell.Y)))
+	}
+	return geometry.Rect{
+		From: geometry.Point{
+			X: from.X - offset.X,
+			Y: from.Y - overhang.Height - offset.Y,
+		},
+		Size: geometry.Size{
+			Width:  to.X - from.X + mapDef.TileSize.Width + overhang.Width + (offset.X * 2),
+			Height: to.Y - from.Y + mapDef.TileSize.Height + overhang.Height + (offset.Y * 2),
+		},
+	}
+}
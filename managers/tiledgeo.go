@@ -0,0 +1,266 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/geometry"
+	"math"
+)
+
+const (
+	orthogonal  = "orthogonal" // orthogonal is a tiled map orthogonal orientation
+	isometric   = "isometric"  // isometric is a tiled map isometric (diamond) orientation
+	staggered   = "staggered"  // staggered is a tiled map staggered isometric orientation
+	hexagonal   = "hexagonal"  // hexagonal is a tiled map hexagonal orientation
+	rightDown   = "right-down" // rightDown is the tiled map render order that start top left
+	rightUp     = "right-up"   // rightUp is the tiled map render order that start bottom left
+	leftDown    = "left-down"  // leftDown is the tiled map render order that start top right
+	leftUp      = "left-up"    // leftUp is the tiled map render order that start bottom right
+	staggerX    = "x"          // staggerX indicates that the staggered axis is the x axis
+	staggerEven = "even"       // staggerEven indicates that the even indexes along the staggered axis are shifted
+)
+
+// hexParams are the params to calculate positions on staggered and hexagonal tiled maps
+type hexParams struct {
+	staggerX    bool    // staggerX indicates if the stagger axis is x
+	staggerEven bool    // staggerEven indicates if the even indexes are shifted
+	sideLengthX float32 // sideLengthX is the hex side length in the x axis
+	sideLengthY float32 // sideLengthY is the hex side length in the y axis
+	sideOffsetX float32 // sideOffsetX is the x distance to the hex side
+	sideOffsetY float32 // sideOffsetY is the y distance to the hex side
+	columnWidth float32 // columnWidth is the width of a column
+	rowHeight   float32 // rowHeight is the height of a row
+}
+
+// getHexParams returns the hexParams for a tiled map
+func getHexParams(def components.TiledMapDef) hexParams {
+	hp := hexParams{
+		staggerX:    def.StaggerAxis == staggerX,
+		staggerEven: def.StaggerIndex == staggerEven,
+	}
+	if def.Data.Orientation == hexagonal {
+		if hp.staggerX {
+			hp.sideLengthX = float32(def.Data.HexSideLength)
+		} else {
+			hp.sideLengthY = float32(def.Data.HexSideLength)
+		}
+	}
+	hp.sideOffsetX = (def.TileSize.Width - hp.sideLengthX) / 2
+	hp.sideOffsetY = (def.TileSize.Height - hp.sideLengthY) / 2
+	hp.columnWidth = hp.sideOffsetX + hp.sideLengthX
+	hp.rowHeight = hp.sideOffsetY + hp.sideLengthY
+	return hp
+}
+
+// doStagger return if a given index in the stagger axis is shifted
+func (hp hexParams) doStagger(index int) bool {
+	return ((index & 1) == 1) != hp.staggerEven
+}
+
+// tileToMap returns the position, in map pixels, of the top left corner of the cell of a tile
+func tileToMap(def components.TiledMapDef, col, row int) geometry.Point {
+	tw := def.TileSize.Width
+	th := def.TileSize.Height
+	switch def.Data.Orientation {
+	case isometric:
+		originX := float32(def.Rows) * tw / 2
+		return geometry.Point{
+			X: (float32(col-row) * tw / 2) + originX - (tw / 2),
+			Y: float32(col+row) * th / 2,
+		}
+	case staggered, hexagonal:
+		hp := getHexParams(def)
+		var pos geometry.Point
+		if hp.staggerX {
+			pos.X = float32(col) * hp.columnWidth
+			pos.Y = float32(row) * (th + hp.sideLengthY)
+			if hp.doStagger(col) {
+				pos.Y += hp.rowHeight
+			}
+		} else {
+			pos.X = float32(col) * (tw + hp.sideLengthX)
+			pos.Y = float32(row) * hp.rowHeight
+			if hp.doStagger(row) {
+				pos.X += hp.columnWidth
+			}
+		}
+		return pos
+	default:
+		return geometry.Point{
+			X: float32(col) * tw,
+			Y: float32(row) * th,
+		}
+	}
+}
+
+// mapToTile returns the tile col and row in a position in map pixels
+func mapToTile(def components.TiledMapDef, pos geometry.Point) (col, row int) {
+	tw := def.TileSize.Width
+	th := def.TileSize.Height
+	switch def.Data.Orientation {
+	case isometric:
+		originX := float32(def.Rows) * tw / 2
+		x := (pos.X - originX) / tw
+		y := pos.Y / th
+		return int(math.Floor(float64(y + x))), int(math.Floor(float64(y - x)))
+	case staggered, hexagonal:
+		return mapToHexTile(def, pos)
+	default:
+		return int(math.Floor(float64(pos.X / tw))), int(math.Floor(float64(pos.Y / th)))
+	}
+}
+
+// mapToHexTile returns the tile col and row in a position in map pixels for staggered and hexagonal maps, we look
+// for the closest tile center around the position
+func mapToHexTile(def components.TiledMapDef, pos geometry.Point) (col, row int) {
+	hp := getHexParams(def)
+
+	// approximate tile
+	var ac, ar int
+	if hp.staggerX {
+		ac = int(math.Floor(float64(pos.X / hp.columnWidth)))
+		ar = int(math.Floor(float64(pos.Y / (def.TileSize.Height + hp.sideLengthY))))
+	} else {
+		ac = int(math.Floor(float64(pos.X / (def.TileSize.Width + hp.sideLengthX))))
+		ar = int(math.Floor(float64(pos.Y / hp.rowHeight)))
+	}
+
+	best := float32(math.MaxFloat32)
+	for r := ar - 1; r <= ar+1; r++ {
+		for c := ac - 1; c <= ac+1; c++ {
+			center := tileToMap(def, c, r)
+			dx := float32(math.Abs(float64(pos.X - (center.X + def.TileSize.Width/2))))
+			dy := float32(math.Abs(float64(pos.Y - (center.Y + def.TileSize.Height/2))))
+			var dist float32
+			if def.Data.Orientation == staggered {
+				// staggered tiles are diamonds
+				dist = dx/def.TileSize.Width + dy/def.TileSize.Height
+			} else {
+				dist = dx*dx + dy*dy
+			}
+			if dist < best {
+				best = dist
+				col, row = c, r
+			}
+		}
+	}
+	return
+}
+
+// tiledMapSize returns the size in pixels of a tiled map
+func tiledMapSize(def components.TiledMapDef) geometry.Size {
+	tw := def.TileSize.Width
+	th := def.TileSize.Height
+	cols := float32(def.Cols)
+	rows := float32(def.Rows)
+	switch def.Data.Orientation {
+	case isometric:
+		return geometry.Size{
+			Width:  (cols + rows) * tw / 2,
+			Height: (cols + rows) * th / 2,
+		}
+	case staggered, hexagonal:
+		hp := getHexParams(def)
+		if hp.staggerX {
+			size := geometry.Size{
+				Width:  (cols * hp.columnWidth) + hp.sideOffsetX,
+				Height: rows * (th + hp.sideLengthY),
+			}
+			if def.Cols > 1 {
+				size.Height += hp.rowHeight
+			}
+			return size
+		}
+		size := geometry.Size{
+			Width:  cols * (tw + hp.sideLengthX),
+			Height: (rows * hp.rowHeight) + hp.sideOffsetY,
+		}
+		if def.Rows > 1 {
+			size.Width += hp.columnWidth
+		}
+		return size
+	default:
+		return geometry.Size{
+			Width:  cols * tw,
+			Height: rows * th,
+		}
+	}
+}
+
+// tileCell is a cell in a tiled map
+type tileCell struct {
+	col int
+	row int
+}
+
+// renderOrderCells returns the cells of a tiled map in the order that they should be drawn
+func renderOrderCells(def components.TiledMapDef) []tileCell {
+	cols := int(def.Cols)
+	rows := int(def.Rows)
+	order := def.Data.RenderOrder
+
+	rowsOrder := make([]int, rows)
+	for i := range rowsOrder {
+		if order == rightUp || order == leftUp {
+			rowsOrder[i] = rows - 1 - i
+		} else {
+			rowsOrder[i] = i
+		}
+	}
+
+	colsOrder := make([]int, cols)
+	for i := range colsOrder {
+		if order == leftDown || order == leftUp {
+			colsOrder[i] = cols - 1 - i
+		} else {
+			colsOrder[i] = i
+		}
+	}
+
+	// in maps staggered in the x axis the shifted columns are lower, so in each row they go after the others
+	staggeredX := false
+	var hp hexParams
+	if def.Data.Orientation == staggered || def.Data.Orientation == hexagonal {
+		hp = getHexParams(def)
+		staggeredX = hp.staggerX
+	}
+
+	cells := make([]tileCell, 0, cols*rows)
+	for _, row := range rowsOrder {
+		if staggeredX {
+			for _, shifted := range []bool{false, true} {
+				for _, col := range colsOrder {
+					if hp.doStagger(col) == shifted {
+						cells = append(cells, tileCell{col: col, row: row})
+					}
+				}
+			}
+		} else {
+			for _, col := range colsOrder {
+				cells = append(cells, tileCell{col: col, row: row})
+			}
+		}
+	}
+	return cells
+}
@@ -0,0 +1,366 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"github.com/juan-medina/gosge/components"
+	gotiled "github.com/lafriks/go-tiled"
+	"io"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// tiledInfiniteAttr matches the infinite attribute of an infinite map
+	tiledInfiniteAttr = regexp.MustCompile(`\sinfinite="1"`)
+	// tiledSizeAttr matches the width and height attributes
+	tiledSizeAttr = regexp.MustCompile(`\s(width|height)="[^"]*"`)
+	// tiledLayerData matches the data element of a tile layer
+	tiledLayerData = regexp.MustCompile(`(?s)<data([^>]*)>(.*?)</data>`)
+	// tiledDataChunk matches a chunk element inside the data of a tile layer
+	tiledDataChunk = regexp.MustCompile(`(?s)<chunk([^>]*)>(.*?)</chunk>`)
+	// tiledDataTile matches a tile element inside xml encoded data
+	tiledDataTile = regexp.MustCompile(`<tile(\s[^>]*)?>`)
+	// tiledAttr matches an attribute in an element
+	tiledAttr = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// tiledChunk is a chunk of tiles of an infinite map layer
+type tiledChunk struct {
+	x, y          int      // x and y are the chunk first cell
+	width, height int      // width and height are the chunk size in cells
+	gids          []uint32 // gids are the chunk global block ids, with flip flags
+}
+
+// tiledAttributes returns the attributes in an element
+func tiledAttributes(element []byte) map[string]string {
+	attrs := make(map[string]string)
+	for _, match := range tiledAttr.FindAllSubmatch(element, -1) {
+		attrs[string(match[1])] = string(match[2])
+	}
+	return attrs
+}
+
+// infiniteMap are the chunks of an infinite map
+type infiniteMap struct {
+	layers      [][]tiledChunk // layers are the chunks for each tile layer, in the order of the file
+	originCol   int            // originCol is the col in the infinite map of the first map col
+	originRow   int            // originRow is the row in the infinite map of the first map row
+	width       int            // width is the number of cols that cover all the chunks
+	height      int            // height is the number of rows that cover all the chunks
+	chunkWidth  int            // chunkWidth is the width in cells of the chunks
+	chunkHeight int            // chunkHeight is the height in cells of the chunks
+}
+
+// flattenInfiniteMap returns a tmx file content where an infinite map is converted in a map of a single empty cell,
+// since go-tiled could not read chunks, and the chunks of each layer, that are keep sparse, so the memory used
+// depends on the chunks and not on the area that they cover
+func flattenInfiniteMap(content []byte) (result []byte, infinite infiniteMap, err error) {
+	// read all the chunks of all the layers
+	blocks := tiledLayerData.FindAllSubmatch(content, -1)
+	infinite.layers = make([][]tiledChunk, len(blocks))
+	minCol, minRow := math.MaxInt32, math.MaxInt32
+	maxCol, maxRow := math.MinInt32, math.MinInt32
+	for i, block := range blocks {
+		attrs := tiledAttributes(block[1])
+		for _, match := range tiledDataChunk.FindAllSubmatch(block[2], -1) {
+			var chunk tiledChunk
+			if chunk, err = readTiledChunk(tiledAttributes(match[1]), attrs["encoding"], attrs["compression"],
+				match[2]); err != nil {
+				return
+			}
+			// all the chunks have the same size and are aligned to it, so we could find them by cell
+			if infinite.chunkWidth == 0 {
+				infinite.chunkWidth, infinite.chunkHeight = chunk.width, chunk.height
+			}
+			if chunk.width != infinite.chunkWidth || chunk.height != infinite.chunkHeight ||
+				chunk.width <= 0 || chunk.height <= 0 ||
+				floorMod(chunk.x, chunk.width) != 0 || floorMod(chunk.y, chunk.height) != 0 {
+				err = fmt.Errorf("unsupported tiled chunk at %d,%d, chunks should have the same size and be aligned",
+					chunk.x, chunk.y)
+				return
+			}
+			infinite.layers[i] = append(infinite.layers[i], chunk)
+			minCol = int(math.Min(float64(minCol), float64(chunk.x)))
+			minRow = int(math.Min(float64(minRow), float64(chunk.y)))
+			maxCol = int(math.Max(float64(maxCol), float64(chunk.x+chunk.width)))
+			maxRow = int(math.Max(float64(maxRow), float64(chunk.y+chunk.height)))
+		}
+	}
+	if minCol > maxCol {
+		// a map without chunks is a single empty cell
+		minCol, minRow, maxCol, maxRow = 0, 0, 1, 1
+		infinite.chunkWidth, infinite.chunkHeight = 1, 1
+	}
+	// keep the first cell even so staggered maps shift the same cells
+	minCol -= minCol & 1
+	minRow -= minRow & 1
+	infinite.originCol, infinite.originRow = minCol, minRow
+	infinite.width, infinite.height = maxCol-minCol, maxRow-minRow
+
+	// go-tiled reads a single empty cell per layer
+	result = tiledLayerData.ReplaceAll(content, []byte(`<data encoding="csv">0</data>`))
+	result = tiledMapTag.ReplaceAllFunc(result, func(tag []byte) []byte {
+		tag = tiledInfiniteAttr.ReplaceAll(tag, []byte(` infinite="0"`))
+		return tiledSizeAttr.ReplaceAllFunc(tag, func(attr []byte) []byte {
+			if bytes.Contains(attr, []byte("width")) {
+				return []byte(` width="1"`)
+			}
+			return []byte(` height="1"`)
+		})
+	})
+	return
+}
+
+// floorDiv returns the integer division of a by b rounded down
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// floorMod returns the modulo of a by b with the sign of b
+func floorMod(a, b int) int {
+	return a - (floorDiv(a, b) * b)
+}
+
+// infiniteChunks returns the components.TiledChunks for the chunks of an infinite map layer
+func infiniteChunks(data *gotiled.Map, chunks []tiledChunk) (result components.TiledChunks, err error) {
+	result = make(components.TiledChunks, len(chunks))
+	for _, chunk := range chunks {
+		tiles := make([]*gotiled.LayerTile, len(chunk.gids))
+		for i, gid := range chunk.gids {
+			if tiles[i], err = data.TileGIDToTile(gid); err != nil {
+				return
+			}
+		}
+		result[components.TiledChunkKey{Col: chunk.x, Row: chunk.y}] = tiles
+	}
+	return
+}
+
+// chunkCell returns the components.TiledChunkKey of the chunk that has a cell of an infinite map, and the index of
+// the cell in the chunk tiles
+func chunkCell(mapDef components.TiledMapDef, col, row int) (key components.TiledChunkKey, index int) {
+	c, r := col+mapDef.OriginCol, row+mapDef.OriginRow
+	key = components.TiledChunkKey{
+		Col: floorDiv(c, mapDef.ChunkWidth) * mapDef.ChunkWidth,
+		Row: floorDiv(r, mapDef.ChunkHeight) * mapDef.ChunkHeight,
+	}
+	index = ((r - key.Row) * mapDef.ChunkWidth) + c - key.Col
+	return
+}
+
+// layerTile returns the tile in a cell of a tile layer, cells without tile or outside the map have a nil tile
+func layerTile(mapDef components.TiledMapDef, layer components.TiledLayerDef, col, row int) *gotiled.LayerTile {
+	if col < 0 || row < 0 || col >= mapDef.Data.Width || row >= mapDef.Data.Height {
+		return gotiled.NilLayerTile
+	}
+	if !mapDef.Infinite {
+		return layer.Tiles.Tiles[(row*mapDef.Data.Width)+col]
+	}
+	key, i := chunkCell(mapDef, col, row)
+	if tiles, ok := layer.Chunks[key]; ok {
+		return tiles[i]
+	}
+	return gotiled.NilLayerTile
+}
+
+// setLayerTile changes the tile in a cell of a tile layer, in infinite maps a chunk is added if the cell does not
+// have one
+func setLayerTile(mapDef components.TiledMapDef, layer components.TiledLayerDef, col, row int,
+	tile *gotiled.LayerTile) {
+	if !mapDef.Infinite {
+		layer.Tiles.Tiles[(row*mapDef.Data.Width)+col] = tile
+		return
+	}
+	key, i := chunkCell(mapDef, col, row)
+	tiles, ok := layer.Chunks[key]
+	if !ok {
+		if tile.IsNil() {
+			return
+		}
+		tiles = make([]*gotiled.LayerTile, mapDef.ChunkWidth*mapDef.ChunkHeight)
+		for j := range tiles {
+			tiles[j] = gotiled.NilLayerTile
+		}
+		layer.Chunks[key] = tiles
+	}
+	tiles[i] = tile
+}
+
+// layerTiles calls a function for each cell of a tile layer that has a tile
+func layerTiles(mapDef components.TiledMapDef, layer components.TiledLayerDef, fn func(col, row int,
+	tile *gotiled.LayerTile)) {
+	if !mapDef.Infinite {
+		for i, tile := range layer.Tiles.Tiles {
+			if !tile.IsNil() {
+				fn(i%mapDef.Data.Width, i/mapDef.Data.Width, tile)
+			}
+		}
+		return
+	}
+	for key, tiles := range layer.Chunks {
+		for i, tile := range tiles {
+			if !tile.IsNil() {
+				fn(key.Col-mapDef.OriginCol+(i%mapDef.ChunkWidth), key.Row-mapDef.OriginRow+(i/mapDef.ChunkWidth), tile)
+			}
+		}
+	}
+}
+
+// mapCells returns the cells of a tiled map that may have tiles in the order that they should be drawn, in infinite
+// maps those are only the cells in the chunks of its layers
+func mapCells(def components.TiledMapDef) []tileCell {
+	if !def.Infinite {
+		return renderOrderCells(def)
+	}
+	keys := make(map[components.TiledChunkKey]bool)
+	for _, layer := range def.Layers {
+		for key := range layer.Chunks {
+			keys[key] = true
+		}
+	}
+	cells := make([]tileCell, 0, len(keys)*def.ChunkWidth*def.ChunkHeight)
+	for key := range keys {
+		for i := 0; i < def.ChunkWidth*def.ChunkHeight; i++ {
+			cells = append(cells, tileCell{
+				col: key.Col - def.OriginCol + (i % def.ChunkWidth),
+				row: key.Row - def.OriginRow + (i / def.ChunkWidth),
+			})
+		}
+	}
+
+	order := def.Data.RenderOrder
+	staggeredX := false
+	var hp hexParams
+	if def.Data.Orientation == staggered || def.Data.Orientation == hexagonal {
+		hp = getHexParams(def)
+		staggeredX = hp.staggerX
+	}
+	// sort them like renderOrderCells does with all the cells of the map
+	sort.Slice(cells, func(i, j int) bool {
+		a, b := cells[i], cells[j]
+		if a.row != b.row {
+			if order == rightUp || order == leftUp {
+				return a.row > b.row
+			}
+			return a.row < b.row
+		}
+		if staggeredX && hp.doStagger(a.col) != hp.doStagger(b.col) {
+			return !hp.doStagger(a.col)
+		}
+		if order == leftDown || order == leftUp {
+			return a.col > b.col
+		}
+		return a.col < b.col
+	})
+	return cells
+}
+
+// readTiledChunk reads the global block ids of a chunk of an infinite map layer
+func readTiledChunk(attrs map[string]string, encoding, compression string, data []byte) (chunk tiledChunk,
+	err error) {
+	for name, value := range attrs {
+		var v int
+		if v, err = strconv.Atoi(value); err != nil {
+			err = fmt.Errorf("invalid tiled chunk %s %q", name, value)
+			return
+		}
+		switch name {
+		case "x":
+			chunk.x = v
+		case "y":
+			chunk.y = v
+		case "width":
+			chunk.width = v
+		case "height":
+			chunk.height = v
+		}
+	}
+
+	switch encoding {
+	case "csv":
+		for _, value := range strings.Split(string(bytes.TrimSpace(data)), ",") {
+			var gid uint64
+			if gid, err = strconv.ParseUint(strings.TrimSpace(value), 10, 32); err != nil {
+				return
+			}
+			chunk.gids = append(chunk.gids, uint32(gid))
+		}
+	case "base64":
+		var r io.Reader = base64.NewDecoder(base64.StdEncoding, bytes.NewReader(bytes.TrimSpace(data)))
+		switch compression {
+		case "gzip":
+			if r, err = gzip.NewReader(r); err != nil {
+				return
+			}
+		case "zlib":
+			if r, err = zlib.NewReader(r); err != nil {
+				return
+			}
+		case "":
+		default:
+			err = fmt.Errorf("unsupported tiled compression %q", compression)
+			return
+		}
+		var raw []byte
+		if raw, err = ioutil.ReadAll(r); err != nil {
+			return
+		}
+		for j := 0; j+4 <= len(raw); j += 4 {
+			chunk.gids = append(chunk.gids, binary.LittleEndian.Uint32(raw[j:]))
+		}
+	case "":
+		for _, match := range tiledDataTile.FindAllSubmatch(data, -1) {
+			var gid uint64
+			if value, ok := tiledAttributes(match[1])["gid"]; ok {
+				if gid, err = strconv.ParseUint(value, 10, 32); err != nil {
+					return
+				}
+			}
+			chunk.gids = append(chunk.gids, uint32(gid))
+		}
+	default:
+		err = fmt.Errorf("unsupported tiled encoding %q", encoding)
+		return
+	}
+
+	if len(chunk.gids) != chunk.width*chunk.height {
+		err = fmt.Errorf("invalid tiled chunk at %d,%d: got %d tiles, expected %d", chunk.x, chunk.y,
+			len(chunk.gids), chunk.width*chunk.height)
+	}
+	return
+}
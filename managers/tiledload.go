@@ -0,0 +1,348 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/lafriks/go-tiled"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// tiledMapTag matches the map element start tag in a tmx file
+	tiledMapTag = regexp.MustCompile(`<map\s[^>]*>`)
+	// tiledStaggerAttr matches the stagger attributes, go-tiled expect them to be numbers so we remove them
+	tiledStaggerAttr = regexp.MustCompile(`\s(staggeraxis|staggerindex)="([^"]*)"`)
+)
+
+// tiledMapFile is a tiled map file with the values that go-tiled could not read
+type tiledMapFile struct {
+	data         *tiled.Map   // data is the map read by go-tiled
+	staggerAxis  string       // staggerAxis is the stagger axis, "x" or "y"
+	staggerIndex string       // staggerIndex is the stagger index, "odd" or "even"
+	root         tiledMapNode // root is the map element with its layers in order
+	infinite     bool         // infinite indicates that this is an infinite map
+	chunks       infiniteMap  // chunks are the chunks of an infinite map
+}
+
+// tiledMapNode is the map element in a tmx file
+type tiledMapNode struct {
+	Children []tiledLayerNode `xml:",any"` // Children are the map child elements in order
+}
+
+// tiledLayerNode is a layer element in a tmx file, go-tiled does not keep the order between layers of different
+// kinds, read the default values of some attributes, or read the tint and parallax
+type tiledLayerNode struct {
+	XMLName   xml.Name         // XMLName is the element name
+	Visible   *int             `xml:"visible,attr"`   // Visible is 0 for hidden layers
+	Opacity   *float32         `xml:"opacity,attr"`   // Opacity is the layer opacity
+	OffsetX   float32          `xml:"offsetx,attr"`   // OffsetX is the layer horizontal offset
+	OffsetY   float32          `xml:"offsety,attr"`   // OffsetY is the layer vertical offset
+	Tint      string           `xml:"tintcolor,attr"` // Tint is the layer tint color
+	ParallaxX *float32         `xml:"parallaxx,attr"` // ParallaxX is the layer horizontal parallax factor
+	ParallaxY *float32         `xml:"parallaxy,attr"` // ParallaxY is the layer vertical parallax factor
+	Children  []tiledLayerNode `xml:",any"`           // Children are the element child elements in order
+}
+
+// tiledLayers are the layers of each kind, in the order that go-tiled read them, in a map or a group
+type tiledLayers struct {
+	tiles   []*tiled.Layer       // tiles are the tile layers
+	objects []*tiled.ObjectGroup // objects are the object layers
+	images  []*tiled.ImageLayer  // images are the image layers
+	groups  []*tiled.Group       // groups are the group layers
+}
+
+// readTiledMap reads a tiled map file
+func readTiledMap(name string) (result tiledMapFile, err error) {
+	var content []byte
+	if content, err = ioutil.ReadFile(name); err != nil {
+		return
+	}
+
+	content = tiledMapTag.ReplaceAllFunc(content, func(tag []byte) []byte {
+		for _, match := range tiledStaggerAttr.FindAllSubmatch(tag, -1) {
+			switch string(match[1]) {
+			case "staggeraxis":
+				result.staggerAxis = string(match[2])
+			case "staggerindex":
+				result.staggerIndex = string(match[2])
+			}
+		}
+		return tiledStaggerAttr.ReplaceAll(tag, nil)
+	})
+
+	if tiledInfiniteAttr.Match(tiledMapTag.Find(content)) {
+		if content, result.chunks, err = flattenInfiniteMap(content); err != nil {
+			return
+		}
+		result.infinite = true
+	}
+
+	if result.data, err = tiled.LoadFromReader(filepath.Dir(name), bytes.NewReader(content)); err != nil {
+		return
+	}
+	if result.infinite {
+		// go-tiled only decode the tiles for the layers that are not in a group, the others need to be decoded with
+		// the placeholder size of the flattened map
+		if err = decodeGroupLayers(result.data, result.data.Groups); err != nil {
+			return
+		}
+		// the map cover all the chunks, but its tiles are only in them
+		result.data.Width, result.data.Height = result.chunks.width, result.chunks.height
+	}
+	err = xml.Unmarshal(content, &result.root)
+	return
+}
+
+// decodeGroupLayers decodes the tiles of the layers in groups, including the nested ones
+func decodeGroupLayers(data *tiled.Map, groups []*tiled.Group) (err error) {
+	for _, group := range groups {
+		for _, layer := range group.Layers {
+			if layer.Tiles == nil {
+				if err = layer.DecodeLayer(data); err != nil {
+					return
+				}
+			}
+		}
+		if err = decodeGroupLayers(data, group.Groups); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// flattenTiledLayers returns the components.TiledLayerDef for the layers of a map or group in draw order, nested
+// layers get the values of their parent applied
+func flattenTiledLayers(data *tiled.Map, nodes []tiledLayerNode, layers tiledLayers,
+	parent components.TiledLayerDef) (result []components.TiledLayerDef, err error) {
+	var tiles, objects, images, groups int
+	for _, node := range nodes {
+		def := parent
+		def.Tiles, def.Objects, def.Image = nil, nil, nil
+		def.Visible = parent.Visible && (node.Visible == nil || *node.Visible != 0)
+		if node.Opacity != nil {
+			def.Opacity *= *node.Opacity
+		}
+		if node.Tint != "" {
+			var tint color.Solid
+			if tint, err = parseTiledColor(node.Tint); err != nil {
+				return
+			}
+			def.Tint = multiplyColor(def.Tint, tint)
+		}
+		def.Offset = def.Offset.Add(geometry.Point{X: node.OffsetX, Y: node.OffsetY})
+		if node.ParallaxX != nil {
+			def.Parallax.X *= *node.ParallaxX
+		}
+		if node.ParallaxY != nil {
+			def.Parallax.Y *= *node.ParallaxY
+		}
+
+		switch node.XMLName.Local {
+		case "layer":
+			if tiles >= len(layers.tiles) {
+				continue
+			}
+			def.Kind = components.TiledTileLayer
+			def.Tiles = layers.tiles[tiles]
+			def.Name = def.Tiles.Name
+			tiles++
+			// go-tiled only decode the tiles for the layers that are not in a group
+			if def.Tiles.Tiles == nil {
+				if err = def.Tiles.DecodeLayer(data); err != nil {
+					return
+				}
+			}
+			result = append(result, def)
+		case "objectgroup":
+			if objects >= len(layers.objects) {
+				continue
+			}
+			def.Kind = components.TiledObjectLayer
+			def.Objects = layers.objects[objects]
+			def.Name = def.Objects.Name
+			objects++
+			result = append(result, def)
+		case "imagelayer":
+			if images >= len(layers.images) {
+				continue
+			}
+			def.Kind = components.TiledImageLayer
+			def.Image = layers.images[images]
+			def.Name = def.Image.Name
+			images++
+			result = append(result, def)
+		case "group":
+			if groups >= len(layers.groups) {
+				continue
+			}
+			group := layers.groups[groups]
+			groups++
+			var children []components.TiledLayerDef
+			if children, err = flattenTiledLayers(data, node.Children, tiledLayers{
+				tiles:   group.Layers,
+				objects: group.ObjectGroups,
+				images:  group.ImageLayers,
+				groups:  group.Groups,
+			}, def); err != nil {
+				return
+			}
+			result = append(result, children...)
+		}
+	}
+	return
+}
+
+// parseTiledColor parse a tiled color in the format #RRGGBB or #AARRGGBB
+func parseTiledColor(value string) (clr color.Solid, err error) {
+	hex := strings.TrimPrefix(value, "#")
+	var v uint64
+	if v, err = strconv.ParseUint(hex, 16, 32); err != nil {
+		err = fmt.Errorf("invalid tiled color %q", value)
+		return
+	}
+	switch len(hex) {
+	case 6:
+		clr = color.Solid{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}
+	case 8:
+		clr = color.Solid{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: uint8(v >> 24)}
+	default:
+		err = fmt.Errorf("invalid tiled color %q", value)
+	}
+	return
+}
+
+// multiplyColor returns the multiplication of two color.Solid
+func multiplyColor(a, b color.Solid) color.Solid {
+	return color.Solid{
+		R: uint8(int(a.R) * int(b.R) / 255),
+		G: uint8(int(a.G) * int(b.G) / 255),
+		B: uint8(int(a.B) * int(b.B) / 255),
+		A: uint8(int(a.A) * int(b.A) / 255),
+	}
+}
+
+// imageLayerSpriteName returns the sprite.Sprite name in the map sheet for an image layer
+func imageLayerSpriteName(layer components.TiledLayerDef) string {
+	return "image:" + layer.Image.Image.Source
+}
+
+// loadImageLayers adds to the map sprite sheet a sprite per each image layer
+func (sm *StorageManager) loadImageLayers(tiledMap *tiled.Map, layers []components.TiledLayerDef,
+	st spriteSheet) (err error) {
+	for _, layer := range layers {
+		if layer.Kind != components.TiledImageLayer || layer.Image.Image == nil || layer.Image.Image.Source == "" {
+			continue
+		}
+		var texture components.TextureDef
+		if texture, err = sm.loadTexture(tiledMap.GetFileFullPath(layer.Image.Image.Source)); err != nil {
+			return
+		}
+		st[imageLayerSpriteName(layer)] = components.SpriteDef{
+			Texture: texture,
+			Origin: geometry.Rect{
+				Size: texture.Size,
+			},
+		}
+	}
+	return
+}
+
+// loadTileset adds to the map sprite sheet a sprite per each tile in a tileset, and keep them by global block id so
+// the tiles drawn in chunks do not need to find them by name
+func (sm *StorageManager) loadTileset(tiledMap *tiled.Map, ts *tiled.Tileset, st spriteSheet,
+	sprites map[uint32]components.SpriteDef) (err error) {
+	// external tilesets are only read by go-tiled when we ask for one of their tiles
+	if _, err = tiledMap.TileGIDToTile(ts.FirstGID); err != nil {
+		return
+	}
+
+	// tilesets without image are a collection of images, one per tile
+	if ts.Image == nil {
+		for _, t := range ts.Tiles {
+			if t.Image == nil {
+				continue
+			}
+			var texture components.TextureDef
+			if texture, err = sm.loadTexture(ts.GetFileFullPath(t.Image.Source)); err != nil {
+				return
+			}
+			def := components.SpriteDef{
+				Texture: texture,
+				Origin: geometry.Rect{
+					Size: texture.Size,
+				},
+			}
+			st[tileSpriteName(ts.FirstGID+t.ID)] = def
+			sprites[ts.FirstGID+t.ID] = def
+		}
+		return
+	}
+
+	var texture components.TextureDef
+	if texture, err = sm.loadTexture(ts.GetFileFullPath(ts.Image.Source)); err != nil {
+		return
+	}
+
+	tilesetTileCount := ts.TileCount
+	tilesetColumns := ts.Columns
+	margin := ts.Margin
+	spacing := ts.Spacing
+
+	if tilesetColumns == 0 {
+		tilesetColumns = (ts.Image.Width - margin + spacing) / (ts.TileWidth + spacing)
+	}
+	if tilesetTileCount == 0 {
+		tilesetTileCount = ((ts.Image.Height - margin + spacing) / (ts.TileHeight + spacing)) * tilesetColumns
+	}
+
+	for i := 0; i < tilesetTileCount; i++ {
+		x := i % tilesetColumns
+		y := i / tilesetColumns
+		def := components.SpriteDef{
+			Texture: texture,
+			Origin: geometry.Rect{
+				From: geometry.Point{
+					X: float32(x*(ts.TileWidth+spacing) + margin),
+					Y: float32(y*(ts.TileHeight+spacing) + margin),
+				},
+				Size: geometry.Size{
+					Width:  float32(ts.TileWidth),
+					Height: float32(ts.TileHeight),
+				},
+			},
+		}
+		st[tileSpriteName(ts.FirstGID+uint32(i))] = def
+		sprites[ts.FirstGID+uint32(i)] = def
+	}
+	return
+}
@@ -26,22 +26,52 @@ import (
 	"fmt"
 	"github.com/juan-medina/goecs"
 	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/color"
 	"github.com/juan-medina/gosge/components/effects"
 	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/shapes"
 	"github.com/juan-medina/gosge/components/sprite"
 	"github.com/juan-medina/gosge/components/tiled"
+	gotiled "github.com/lafriks/go-tiled"
 	"strconv"
 )
 
 type tiledManager struct {
-	sm *StorageManager
+	sm        *StorageManager
+	factories map[string]tiled.ObjectFactory
+	streams   map[goecs.EntityID][]cellChunk // streams are the chunks per each tiled.Stream map entity
+	entities  map[goecs.EntityID]mapEntities // entities are the entities that move with each map entity
+}
+
+// mapEntities are the entities, other than the tiled.Chunk, that has been added for a map entity
+type mapEntities map[goecs.EntityID]bool
+
+// track adds an entity to the ones that move with a map entity
+func (tm tiledManager) track(mapID, id goecs.EntityID) {
+	entities, ok := tm.entities[mapID]
+	if !ok {
+		entities = make(mapEntities)
+		tm.entities[mapID] = entities
+	}
+	entities[id] = true
+}
+
+// untrack removes an entity from the ones that move with a map entity
+func (tm tiledManager) untrack(mapID, id goecs.EntityID) {
+	if entities, ok := tm.entities[mapID]; ok {
+		delete(entities, id)
+	}
 }
 
 const (
-	rightDown = "right-down"
+	ySortProperty = "ysort"    // ySortProperty is the layer bool property to sort its tiles with effects.YSort
+	tileGIDMask   = 0x0fffffff // tileGIDMask is the mask to remove the flip bits of a tile gid
+	tileFlipXMask = 0x80000000 // tileFlipXMask is the bit that indicates that a tile gid is flipped horizontally
+	tileFlipYMask = 0x40000000 // tileFlipYMask is the bit that indicates that a tile gid is flipped vertically
 )
 
-func (tm tiledManager) System(world *goecs.World, _ float32) (err error) {
+func (tm tiledManager) System(world *goecs.World, delta float32) (err error) {
+	tm.pruneMaps(world)
 	for it := world.Iterator(tiled.TYPE.Map, geometry.TYPE.Point); it != nil; it = it.Next() {
 		ent := it.Value()
 		tiledMap := tiled.Get.Map(ent)
@@ -52,9 +82,25 @@ func (tm tiledManager) System(world *goecs.World, _ float32) (err error) {
 				depth = effects.Get.Layer(ent).Depth
 			}
 
-			if err = tm.addSpriteFromTiledMap(world, tiledMap, depth, pos); err == nil {
-				pos := geometry.Get.Point(ent)
-				ent.Set(tiled.MapState{Position: pos, Scale: tiledMap.Scale})
+			// the map state is set even if we fail, so what has been added is not added again
+			delete(tm.entities, ent.ID())
+			delete(tm.streams, ent.ID())
+			err = tm.addLayersFromTiledMap(world, ent.ID(), tiledMap, depth, pos)
+			pos := geometry.Get.Point(ent)
+			ent.Set(tiled.MapState{
+				Origin:     pos,
+				Position:   pos,
+				Scale:      tiledMap.Scale,
+				Animations: make(map[uint32]tiled.TileAnimationState),
+				Chunks:     make(map[tiled.ChunkKey][]goecs.EntityID),
+			})
+			if err != nil {
+				return
+			}
+			if ent.Contains(tiled.TYPE.Stream) {
+				if err = tm.streamChunks(world, ent, tiledMap, tiled.Get.MapState(ent)); err != nil {
+					return
+				}
 			}
 		} else {
 			state := tiled.Get.MapState(ent)
@@ -64,100 +110,580 @@ func (tm tiledManager) System(world *goecs.World, _ float32) (err error) {
 					X: state.Position.X - pos.X,
 					Y: state.Position.Y - pos.Y,
 				}
-				tm.updateSprites(world, tiledMap, diff)
+				if err = tm.updateSprites(world, ent.ID(), tiledMap, diff); err != nil {
+					return
+				}
 				state.Position = pos
 				state.Scale = tiledMap.Scale
 				ent.Set(state)
 			}
+			if state.Animations == nil || state.Chunks == nil {
+				if state.Animations == nil {
+					state.Animations = make(map[uint32]tiled.TileAnimationState)
+				}
+				if state.Chunks == nil {
+					state.Chunks = make(map[tiled.ChunkKey][]goecs.EntityID)
+				}
+				ent.Set(state)
+			}
+			if err = tm.animateTiles(world, tiledMap, state, delta); err != nil {
+				return
+			}
+			if ent.Contains(tiled.TYPE.Stream) {
+				if err = tm.streamChunks(world, ent, tiledMap, state); err != nil {
+					return
+				}
+			}
 		}
 	}
 	return
 }
 
-func (tm *tiledManager) GetTilePosition(x, y int, def components.TiledMapDef) geometry.Point {
-	return geometry.Point{
-		X: float32(x * def.Data.TileWidth),
-		Y: float32(y * def.Data.TileHeight),
+// pruneMaps forgets the streams and the entities of the maps that has been removed, like the ones in a stage that
+// has change
+func (tm tiledManager) pruneMaps(world *goecs.World) {
+	removed := func(id goecs.EntityID) bool {
+		ent := world.Get(id)
+		return ent == nil || ent.ID() != id || ent.NotContains(tiled.TYPE.Map)
+	}
+	for id := range tm.streams {
+		if removed(id) {
+			delete(tm.streams, id)
+		}
+	}
+	for id := range tm.entities {
+		if removed(id) {
+			delete(tm.entities, id)
+		}
 	}
 }
 
-func (tm tiledManager) addSpriteFromTiledMap(world *goecs.World, tiledMap tiled.Map, depth float32, mapPos geometry.Point) (err error) {
-	if mapDef, err := tm.sm.GetTiledMapDef(tiledMap.Name); err == nil {
-		if !(mapDef.Data.RenderOrder == "" || mapDef.Data.RenderOrder == rightDown) {
-			return fmt.Errorf("unsupported tiled render order : got %q, want %q", mapDef.Data.RenderOrder, rightDown)
+// animateTiles advance the animations of a tiled.Map, there is one clock per animated global block id so all the
+// tiles with the same id are in sync, and only the tiles that have change frame are updated
+func (tm tiledManager) animateTiles(world *goecs.World, tiledMap tiled.Map, state tiled.MapState,
+	delta float32) (err error) {
+	var mapDef components.TiledMapDef
+	if mapDef, err = tm.sm.GetTiledMapDef(tiledMap.Name); err != nil || len(mapDef.Animations) == 0 {
+		return
+	}
+
+	var changed map[uint32]string
+	for gid, frames := range mapDef.Animations {
+		as := state.Animations[gid]
+		as.Time += delta
+		old := as.Frame
+		// skip as many frames as we need, frames without duration are never show
+		for i := 0; i < len(frames) && as.Time >= frames[as.Frame].Duration; i++ {
+			as.Time -= frames[as.Frame].Duration
+			as.Frame = (as.Frame + 1) % len(frames)
+		}
+		if as.Time >= frames[as.Frame].Duration {
+			as.Time = 0
 		}
+		state.Animations[gid] = as
+		if as.Frame != old {
+			if changed == nil {
+				changed = make(map[uint32]string)
+			}
+			changed[gid] = tileSpriteName(frames[as.Frame].GID)
+		}
+	}
 
-		ld := depth
-		for _, l := range mapDef.Data.Layers {
-			if !l.Visible {
-				ld--
+	if changed != nil {
+		for it := world.Iterator(tiled.TYPE.AnimatedTile, sprite.TYPE); it != nil; it = it.Next() {
+			ent := it.Value()
+			at := tiled.Get.AnimatedTile(ent)
+			if at.Map != tiledMap.Name {
 				continue
 			}
-			var xs, xe, xi, ys, ye, yi int
-			xs = 0
-			xe = mapDef.Data.Width
-			xi = 1
-			ys = 0
-			ye = mapDef.Data.Height
-			yi = 1
-
-			i := 0
-			var id uint32
-			for y := ys; y*yi < ye; y = y + yi {
-				for x := xs; x*xi < xe; x = x + xi {
-					if l.Tiles[i].IsNil() {
-						i++
-						continue
-					}
-					id = l.Tiles[i].ID
-					sprName := strconv.Itoa(int(id))
-					pos := tm.GetTilePosition(x, y, mapDef)
-					pos.X = (pos.X * tiledMap.Scale) + mapPos.X
-					pos.Y = (pos.Y * tiledMap.Scale) + mapPos.Y
-
-					world.AddEntity(
-						sprite.Sprite{
-							Sheet: tiledMap.Name,
-							Name:  sprName,
-							Scale: tiledMap.Scale,
-							FlipX: l.Tiles[i].HorizontalFlip,
-							FlipY: l.Tiles[i].VerticalFlip,
-						},
-						tiled.BlockInfo{
-							Properties: mapDef.Properties[id],
-							Layer:      l.Name,
-							Row:        y,
-							Col:        x,
-						},
-						pos,
-						effects.Layer{Depth: ld},
-					)
-					i++
+			if name, ok := changed[at.GID]; ok {
+				spr := sprite.Get(ent)
+				spr.Name = name
+				ent.Set(spr)
+			}
+		}
+	}
+	return
+}
+
+// addAnimatedTile adds to a tile entity, if its global block id is animated, a tiled.AnimatedTile
+func (tm tiledManager) addAnimatedTile(world *goecs.World, mapDef components.TiledMapDef, tiledMap tiled.Map,
+	id goecs.EntityID, gid uint32) {
+	if frames, ok := mapDef.Animations[gid]; ok && len(frames) > 0 {
+		ent := world.Get(id)
+		spr := sprite.Get(ent)
+		spr.Name = tileSpriteName(frames[0].GID)
+		ent.Set(spr)
+		ent.Add(tiled.AnimatedTile{Map: tiledMap.Name, GID: gid})
+	}
+}
+
+// getMap returns the tiled.Map, the world geometry.Point of its tiles and its components.TiledMapDef from a map entity
+func (tm tiledManager) getMap(world *goecs.World, mapID goecs.EntityID) (tiledMap tiled.Map, pos geometry.Point,
+	def components.TiledMapDef, err error) {
+	ent := world.Get(mapID)
+	if ent == nil || ent.ID() != mapID || ent.NotContains(tiled.TYPE.Map) || ent.NotContains(geometry.TYPE.Point) {
+		err = fmt.Errorf("entity %d is not a tiled map", mapID)
+		return
+	}
+	tiledMap = tiled.Get.Map(ent)
+	pos = geometry.Get.Point(ent)
+	if ent.Contains(tiled.TYPE.MapState) {
+		// the map tiles move opposite to the horizontal movement of the map
+		origin := tiled.Get.MapState(ent).Origin
+		pos.X = origin.X - (pos.X - origin.X)
+	}
+	def, err = tm.sm.GetTiledMapDef(tiledMap.Name)
+	return
+}
+
+// TileToWorld returns the world geometry.Point of the top left corner of a tile cell in a map entity
+func (tm tiledManager) TileToWorld(world *goecs.World, mapID goecs.EntityID, col, row int) (geometry.Point, error) {
+	tiledMap, pos, def, err := tm.getMap(world, mapID)
+	if err != nil {
+		return geometry.Point{}, err
+	}
+	mp := tileToMap(def, col, row)
+	return geometry.Point{
+		X: (mp.X * tiledMap.Scale) + pos.X,
+		Y: (mp.Y * tiledMap.Scale) + pos.Y,
+	}, nil
+}
+
+// WorldToTile returns the tile col and row in a world geometry.Point for a map entity
+func (tm tiledManager) WorldToTile(world *goecs.World, mapID goecs.EntityID, at geometry.Point) (col, row int,
+	err error) {
+	var tiledMap tiled.Map
+	var pos geometry.Point
+	var def components.TiledMapDef
+	if tiledMap, pos, def, err = tm.getMap(world, mapID); err == nil {
+		col, row = mapToTile(def, geometry.Point{
+			X: (at.X - pos.X) / tiledMap.Scale,
+			Y: (at.Y - pos.Y) / tiledMap.Scale,
+		})
+	}
+	return
+}
+
+// addLayersFromTiledMap adds the map background and the entities for each of the map layers, each layer in its
+// own depth starting from the map depth
+func (tm tiledManager) addLayersFromTiledMap(world *goecs.World, mapID goecs.EntityID, tiledMap tiled.Map,
+	depth float32, mapPos geometry.Point) (err error) {
+	var mapDef components.TiledMapDef
+	if mapDef, err = tm.sm.GetTiledMapDef(tiledMap.Name); err != nil {
+		return
+	}
+
+	switch mapDef.Data.RenderOrder {
+	case "", rightDown, rightUp, leftDown, leftUp:
+	default:
+		err = fmt.Errorf("unsupported tiled render order : got %q", mapDef.Data.RenderOrder)
+		return
+	}
+
+	switch mapDef.Data.Orientation {
+	case "", orthogonal, isometric, staggered, hexagonal:
+	default:
+		err = fmt.Errorf("unsupported tiled orientation : got %q", mapDef.Data.Orientation)
+		return
+	}
+
+	// the background is added first so it is draw before the first layer
+	if mapDef.Background.A != 0 {
+		tm.track(mapID, world.AddEntity(
+			shapes.SolidBox{
+				Size:  mapDef.Size,
+				Scale: tiledMap.Scale,
+			},
+			mapDef.Background,
+			tiled.LayerInfo{Map: tiledMap.Name},
+			mapPos,
+			effects.Layer{Depth: depth},
+		))
+	}
+
+	cells := mapCells(mapDef)
+
+	// the tiles of streamed maps are spawned by chunks around their focus
+	streamed := world.Get(mapID).Contains(tiled.TYPE.Stream)
+	if streamed {
+		tm.streams[mapID] = chunkCells(mapDef, cells, streamChunkSize(tiledMap))
+	}
+
+	for i, layer := range mapDef.Layers {
+		ld := depth - float32(i)
+		base := layerPosition(layer, tiledMap.Scale, mapPos, mapPos)
+		switch layer.Kind {
+		case components.TiledTileLayer:
+			if !layer.Visible {
+				continue
+			}
+			if streamed {
+				continue
+			}
+			if tm.chunkedLayer(tiledMap, layer) {
+				for _, chunk := range chunkCells(mapDef, cells, tiledMap.ChunkSize) {
+					tm.addChunk(world, mapID, mapDef, i, ld, chunk)
 				}
+				continue
+			}
+			for _, cell := range cells {
+				tile := layerTile(mapDef, layer, cell.col, cell.row)
+				if tile.IsNil() {
+					continue
+				}
+				tm.track(mapID, tm.addTile(world, mapDef, tiledMap, base, layer, ld, cell, tile))
+			}
+		case components.TiledObjectLayer:
+			// we keep adding the rest of the map and return the first error
+			if objErr := tm.addObjects(world, mapID, mapDef, tiledMap, layer, ld, base); objErr != nil && err == nil {
+				err = objErr
+			}
+		case components.TiledImageLayer:
+			if layer.Visible {
+				tm.addImageLayer(world, mapID, tiledMap, layer, ld, base)
 			}
-			ld--
 		}
 	}
 
 	return
 }
 
-func (tm tiledManager) updateSprites(world *goecs.World, tiledMap tiled.Map, diff geometry.Point) {
-	for it := world.Iterator(sprite.TYPE, geometry.TYPE.Point); it != nil; it = it.Next() {
-		ent := it.Value()
-		spr := sprite.Get(ent)
-		if spr.Sheet == tiledMap.Name {
-			pos := geometry.Get.Point(ent)
-			pos.X += diff.X
-			pos.Y -= diff.Y
-			ent.Set(pos)
+// layerPosition returns the world position for the top left of a layer, layers with parallax move from the map
+// origin multiplied by their factor, as the map tiles they move opposite to the horizontal movement of the map
+func layerPosition(layer components.TiledLayerDef, scale float32, origin, mapPos geometry.Point) geometry.Point {
+	return geometry.Point{
+		X: origin.X - ((mapPos.X - origin.X) * layer.Parallax.X) + (layer.Offset.X * scale),
+		Y: origin.Y + ((mapPos.Y - origin.Y) * layer.Parallax.Y) + (layer.Offset.Y * scale),
+	}
+}
+
+// layerTint returns the color.Solid to tint the entities of a layer with its tint and opacity, and false if the
+// layer does not need to be tinted
+func layerTint(layer components.TiledLayerDef) (tint color.Solid, ok bool) {
+	tint = layer.Tint
+	tint.A = uint8(float32(tint.A) * layer.Opacity)
+	return tint, tint != color.White
+}
+
+// addImageLayer adds the entity for an image layer
+func (tm tiledManager) addImageLayer(world *goecs.World, mapID goecs.EntityID, tiledMap tiled.Map,
+	layer components.TiledLayerDef, depth float32, base geometry.Point) {
+	if layer.Image.Image == nil || layer.Image.Image.Source == "" {
+		return
+	}
+	info := tiled.LayerInfo{
+		Map:        tiledMap.Name,
+		Layer:      layer.Name,
+		Properties: make(map[string]string),
+	}
+	for _, p := range layer.Image.Properties {
+		info.Properties[p.Name] = p.Value
+	}
+	ent := world.AddEntity(
+		sprite.Sprite{
+			Sheet: tiledMap.Name,
+			Name:  imageLayerSpriteName(layer),
+			Scale: tiledMap.Scale,
+		},
+		info,
+		geometry.Point{
+			X: base.X + (float32(layer.Image.X) * tiledMap.Scale),
+			Y: base.Y + (float32(layer.Image.Y) * tiledMap.Scale),
+		},
+		effects.Layer{Depth: depth},
+	)
+	tm.track(mapID, ent)
+	if tint, ok := layerTint(layer); ok {
+		world.Get(ent).Add(tint)
+	}
+}
+
+// addTile adds the entity for a tile in a layer cell
+func (tm tiledManager) addTile(world *goecs.World, mapDef components.TiledMapDef, tiledMap tiled.Map,
+	mapPos geometry.Point, layer components.TiledLayerDef, depth float32, cell tileCell,
+	tile *gotiled.LayerTile) goecs.EntityID {
+	gid := tile.Tileset.FirstGID + tile.ID
+	sprName := tileSpriteName(gid)
+	pos, bottom := tm.tileDrawPosition(mapDef, tiledMap, mapPos, cell.col, cell.row, sprName, tile.Tileset)
+
+	tileID := world.AddEntity(
+		sprite.Sprite{
+			Sheet: tiledMap.Name,
+			Name:  sprName,
+			Scale: tiledMap.Scale,
+			FlipX: tile.HorizontalFlip,
+			FlipY: tile.VerticalFlip,
+		},
+		tiled.BlockInfo{
+			Properties: mapDef.Properties[gid],
+			Map:        tiledMap.Name,
+			Layer:      layer.Name,
+			Row:        cell.row,
+			Col:        cell.col,
+			GID:        gid,
+		},
+		pos,
+		effects.Layer{Depth: depth},
+	)
+	tm.addAnimatedTile(world, mapDef, tiledMap, tileID, gid)
+	if layer.Tiles.Properties.GetBool(ySortProperty) {
+		// tiles are sorted by its bottom
+		world.Get(tileID).Add(effects.YSort{Offset: bottom - pos.Y})
+	}
+	if tint, ok := layerTint(layer); ok {
+		world.Get(tileID).Add(tint)
+	}
+	return tileID
+}
+
+// tileDrawPosition returns the world position to draw a tile sprite in a cell, and the world Y of the cell bottom,
+// tiles are aligned to the bottom left of the cell and moved by their tileset offset
+func (tm tiledManager) tileDrawPosition(mapDef components.TiledMapDef, tiledMap tiled.Map, mapPos geometry.Point,
+	col, row int, sprName string, ts *gotiled.Tileset) (pos geometry.Point, bottom float32) {
+	cell := tileToMap(mapDef, col, row)
+	pos = cell
+	if def, err := tm.sm.GetSpriteDef(tiledMap.Name, sprName); err == nil {
+		pos = pos.Add(tileOffset(mapDef, def, ts))
+	}
+	pos.X = (pos.X * tiledMap.Scale) + mapPos.X
+	pos.Y = (pos.Y * tiledMap.Scale) + mapPos.Y
+	bottom = ((cell.Y + mapDef.TileSize.Height) * tiledMap.Scale) + mapPos.Y
+	return
+}
+
+// tileOffset returns the offset, in map pixels, from the top left of a cell to draw a tile sprite, aligning it to the
+// bottom left of the cell and moving it by its tileset offset
+func tileOffset(mapDef components.TiledMapDef, def components.SpriteDef, ts *gotiled.Tileset) geometry.Point {
+	offset := geometry.Point{Y: mapDef.TileSize.Height - def.Origin.Size.Height}
+	if ts != nil && ts.TileOffset != nil {
+		offset.X += float32(ts.TileOffset.X)
+		offset.Y += float32(ts.TileOffset.Y)
+	}
+	return offset
+}
+
+// tileSpriteName returns the sprite.Sprite name in the map sheet for a tile global id
+func tileSpriteName(gid uint32) string {
+	return strconv.Itoa(int((gid & tileGIDMask) - 1))
+}
+
+// objectToMap returns the position in map pixels of a position in object coordinates, on isometric maps objects are
+// in tile coordinates with the tile height as unit
+func objectToMap(def components.TiledMapDef, x, y float64) geometry.Point {
+	// objects in infinite maps are relative to the infinite map origin
+	if def.Infinite {
+		if def.Data.Orientation == isometric {
+			x -= float64(def.OriginCol) * float64(def.TileSize.Height)
+			y -= float64(def.OriginRow) * float64(def.TileSize.Height)
+		} else {
+			origin := tileToMap(def, def.OriginCol, def.OriginRow)
+			x -= float64(origin.X)
+			y -= float64(origin.Y)
+		}
+	}
+	if def.Data.Orientation == isometric {
+		tx := float32(x) / def.TileSize.Height
+		ty := float32(y) / def.TileSize.Height
+		originX := float32(def.Rows) * def.TileSize.Width / 2
+		return geometry.Point{
+			X: ((tx - ty) * def.TileSize.Width / 2) + originX,
+			Y: (tx + ty) * def.TileSize.Height / 2,
 		}
 	}
+	return geometry.Point{X: float32(x), Y: float32(y)}
+}
+
+// addObjects adds an entity per object in an object layer, objects in hidden layers are added but their tiles are
+// hidden
+func (tm tiledManager) addObjects(world *goecs.World, mapID goecs.EntityID, mapDef components.TiledMapDef,
+	tiledMap tiled.Map, layer components.TiledLayerDef, depth float32, base geometry.Point) (err error) {
+	for _, obj := range layer.Objects.Objects {
+		info := tiled.ObjectInfo{
+			ID:         obj.ID,
+			Name:       obj.Name,
+			Class:      obj.Type,
+			Map:        tiledMap.Name,
+			Layer:      layer.Name,
+			Properties: make(map[string]string),
+			Shape:      tiled.RectangleShape,
+			Size: geometry.Size{
+				Width:  float32(obj.Width) * tiledMap.Scale,
+				Height: float32(obj.Height) * tiledMap.Scale,
+			},
+			Rotation: float32(obj.Rotation),
+			GID:      obj.GID & tileGIDMask,
+		}
+		for _, p := range obj.Properties {
+			info.Properties[p.Name] = p.Value
+		}
+
+		origin := objectToMap(mapDef, obj.X, obj.Y)
+
+		var points []*gotiled.Point
+		switch {
+		case obj.GID != 0:
+			info.Shape = tiled.TileShape
+		case obj.Text != nil:
+			info.Shape = tiled.TextShape
+			info.Text = obj.Text.Text
+		case len(obj.Ellipses) > 0:
+			info.Shape = tiled.EllipseShape
+		case len(obj.Polygons) > 0 && obj.Polygons[0].Points != nil:
+			info.Shape = tiled.PolygonShape
+			points = *obj.Polygons[0].Points
+		case len(obj.PolyLines) > 0 && obj.PolyLines[0].Points != nil:
+			info.Shape = tiled.PolylineShape
+			points = *obj.PolyLines[0].Points
+		case obj.Width == 0 && obj.Height == 0:
+			info.Shape = tiled.PointShape
+		}
+
+		for _, p := range points {
+			point := objectToMap(mapDef, obj.X+p.X, obj.Y+p.Y)
+			info.Points = append(info.Points, geometry.Point{
+				X: (point.X - origin.X) * tiledMap.Scale,
+				Y: (point.Y - origin.Y) * tiledMap.Scale,
+			})
+		}
+
+		pos := geometry.Point{
+			X: (origin.X * tiledMap.Scale) + base.X,
+			Y: (origin.Y * tiledMap.Scale) + base.Y,
+		}
+
+		var objID goecs.EntityID
+		if info.Shape == tiled.TileShape {
+			objID = tm.addTileObject(world, tiledMap, mapDef, layer, obj, info, pos, depth)
+		} else {
+			objID = world.AddEntity(info, pos)
+		}
+		tm.track(mapID, objID)
+
+		// we keep adding the rest of the objects and return the first error
+		if factory, ok := tm.factories[info.Class]; ok {
+			if factoryErr := factory(world, world.Get(objID)); factoryErr != nil && err == nil {
+				err = fmt.Errorf("error creating tiled object %d of class %q: %v", obj.ID, info.Class, factoryErr)
+			}
+		}
+	}
+	return
+}
+
+// addTileObject adds a tiled.TileShape object with its sprite.Sprite
+func (tm tiledManager) addTileObject(world *goecs.World, tiledMap tiled.Map, mapDef components.TiledMapDef,
+	layer components.TiledLayerDef, obj *gotiled.Object, info tiled.ObjectInfo, pos geometry.Point,
+	depth float32) goecs.EntityID {
+	spr := sprite.Sprite{
+		Sheet:    tiledMap.Name,
+		Name:     tileSpriteName(obj.GID),
+		Rotation: float32(obj.Rotation),
+		Scale:    tiledMap.Scale,
+		FlipX:    obj.GID&tileFlipXMask != 0,
+		FlipY:    obj.GID&tileFlipYMask != 0,
+	}
+
+	// tile objects are scaled to the object size
+	if def, err := tm.sm.GetSpriteDef(spr.Sheet, spr.Name); err == nil && def.Origin.Size.Width > 0 && obj.Width > 0 {
+		spr.Scale = tiledMap.Scale * float32(obj.Width) / def.Origin.Size.Width
+	}
+
+	// tile objects position is their bottom left, or bottom center on isometric maps
+	pos.Y -= info.Size.Height
+	if mapDef.Data.Orientation == isometric {
+		pos.X -= info.Size.Width / 2
+	}
+
+	// and they are moved by their tileset offset
+	if tile, err := mapDef.Data.TileGIDToTile(obj.GID); err == nil && tile.Tileset != nil &&
+		tile.Tileset.TileOffset != nil {
+		pos.X += float32(tile.Tileset.TileOffset.X) * tiledMap.Scale
+		pos.Y += float32(tile.Tileset.TileOffset.Y) * tiledMap.Scale
+	}
+
+	id := world.AddEntity(info, spr, pos, effects.Layer{Depth: depth})
+	tm.addAnimatedTile(world, mapDef, tiledMap, id, info.GID)
+	if tint, ok := layerTint(layer); ok {
+		world.Get(id).Add(tint)
+	}
+	if !layer.Visible {
+		world.Get(id).Add(effects.Hide{})
+	}
+	return id
+}
+
+// RegisterObjectFactory register a tiled.ObjectFactory for the objects of a given class
+func (tm tiledManager) RegisterObjectFactory(class string, factory tiled.ObjectFactory) {
+	tm.factories[class] = factory
+}
+
+// updateSprites moves the entities of a map, the ones in a parallax layer move relative to their factor, the
+// tiled.Chunk are not moved since they are draw from the map position
+func (tm tiledManager) updateSprites(world *goecs.World, mapID goecs.EntityID, tiledMap tiled.Map,
+	diff geometry.Point) (err error) {
+	var mapDef components.TiledMapDef
+	if mapDef, err = tm.sm.GetTiledMapDef(tiledMap.Name); err != nil {
+		return
+	}
+	factors := make(map[string]geometry.Point, len(mapDef.Layers))
+	for _, layer := range mapDef.Layers {
+		factors[layer.Name] = layer.Parallax
+	}
+
+	entities := tm.entities[mapID]
+	for id := range entities {
+		ent := world.Get(id)
+		if ent == nil || ent.ID() != id || ent.NotContains(geometry.TYPE.Point) {
+			// the entity has been removed
+			delete(entities, id)
+			continue
+		}
+		layer := ""
+		switch {
+		case ent.Contains(tiled.TYPE.BlockInfo):
+			layer = tiled.Get.BlockInfo(ent).Layer
+		case ent.Contains(tiled.TYPE.ObjectInfo):
+			layer = tiled.Get.ObjectInfo(ent).Layer
+		case ent.Contains(tiled.TYPE.LayerInfo):
+			layer = tiled.Get.LayerInfo(ent).Layer
+		}
+		factor := geometry.Point{X: 1, Y: 1}
+		if f, ok := factors[layer]; ok && layer != "" {
+			factor = f
+		}
+		pos := geometry.Get.Point(ent)
+		pos.X += diff.X * factor.X
+		pos.Y -= diff.Y * factor.Y
+		ent.Set(pos)
+	}
+	return
+}
+
+// TiledMapper is a manager that handle tiled maps
+type TiledMapper interface {
+	WithSystem
+	// TileToWorld returns the world geometry.Point of the top left corner of a tile cell in a map entity
+	TileToWorld(world *goecs.World, mapID goecs.EntityID, col, row int) (geometry.Point, error)
+	// WorldToTile returns the tile col and row in a world geometry.Point for a map entity
+	WorldToTile(world *goecs.World, mapID goecs.EntityID, at geometry.Point) (col, row int, err error)
+	// RegisterObjectFactory register a tiled.ObjectFactory for the objects of a given class
+	RegisterObjectFactory(class string, factory tiled.ObjectFactory)
+	// GetTile returns the tiled.BlockInfo for a tile in a layer of a map entity
+	GetTile(world *goecs.World, mapID goecs.EntityID, layer string, col, row int) (tiled.BlockInfo, error)
+	// SetTile changes the tile in a layer of a map entity to a global block id, or 0 to remove it
+	SetTile(world *goecs.World, mapID goecs.EntityID, layer string, col, row int, gid uint32) error
+	// FindTiles returns the tiled.BlockInfo for the tiles in a layer of a map entity that have a property with a
+	// value, if the layer is empty it will search in all the layers
+	FindTiles(world *goecs.World, mapID goecs.EntityID, layer string, property string,
+		value string) ([]tiled.BlockInfo, error)
 }
 
-// TiledMaps returns a managers.WithSystem that handle tiled maps
-func TiledMaps(sm *StorageManager) WithSystem {
+// TiledMaps returns a managers.TiledMapper that handle tiled maps
+func TiledMaps(sm *StorageManager) TiledMapper {
 	return tiledManager{
-		sm: sm,
+		sm:        sm,
+		factories: make(map[string]tiled.ObjectFactory),
+		streams:   make(map[goecs.EntityID][]cellChunk),
+		entities:  make(map[goecs.EntityID]mapEntities),
 	}
 }
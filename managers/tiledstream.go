@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/tiled"
+	"math"
+)
+
+const (
+	defaultStreamChunkSize = 16  // defaultStreamChunkSize is the chunk size of a tiled.Stream map without ChunkSize
+	streamKeepFactor       = 1.5 // streamKeepFactor is how far, relative to the tiled.Stream radius, chunks are kept
+)
+
+// streamChunkSize returns the size in cells of the chunks of a tiled.Stream map
+func streamChunkSize(tiledMap tiled.Map) int {
+	if tiledMap.ChunkSize > 0 {
+		return tiledMap.ChunkSize
+	}
+	return defaultStreamChunkSize
+}
+
+// rectDistance returns the distance from a point to a geometry.Rect, 0 if is inside
+func rectDistance(point geometry.Point, rect geometry.Rect) float32 {
+	dx := math.Max(math.Max(float64(rect.From.X-point.X), 0), float64(point.X-(rect.From.X+rect.Size.Width)))
+	dy := math.Max(math.Max(float64(rect.From.Y-point.Y), 0), float64(point.Y-(rect.From.Y+rect.Size.Height)))
+	return float32(math.Sqrt(dx*dx + dy*dy))
+}
+
+// streamChunks spawns the chunks of the tile layers of a tiled.Stream map that are near its focus, and despawns the
+// ones that are far away
+func (tm tiledManager) streamChunks(world *goecs.World, ent *goecs.Entity, tiledMap tiled.Map,
+	state tiled.MapState) (err error) {
+	var mapDef components.TiledMapDef
+	if mapDef, err = tm.sm.GetTiledMapDef(tiledMap.Name); err != nil {
+		return
+	}
+	chunks, ok := tm.streams[ent.ID()]
+	if !ok {
+		chunks = chunkCells(mapDef, mapCells(mapDef), streamChunkSize(tiledMap))
+		tm.streams[ent.ID()] = chunks
+	}
+
+	stream := tiled.Get.Stream(ent)
+	focus := stream.Point
+	if stream.Focus != 0 {
+		if focusEnt := world.Get(stream.Focus); focusEnt != nil && focusEnt.ID() == stream.Focus &&
+			focusEnt.Contains(geometry.TYPE.Point) {
+			focus = geometry.Get.Point(focusEnt)
+		}
+	}
+
+	depth := DefaultLayer
+	if ent.Contains(effects.TYPE.Layer) {
+		depth = effects.Get.Layer(ent).Depth
+	}
+
+	for li, layer := range mapDef.Layers {
+		if layer.Kind != components.TiledTileLayer || !layer.Visible {
+			continue
+		}
+		base := layerPosition(layer, tiledMap.Scale, state.Origin, state.Position)
+		chunked := tm.chunkedLayer(tiledMap, layer)
+		for _, chunk := range chunks {
+			bounds := geometry.Rect{
+				From: geometry.Point{
+					X: (chunk.bounds.From.X * tiledMap.Scale) + base.X,
+					Y: (chunk.bounds.From.Y * tiledMap.Scale) + base.Y,
+				},
+				Size: chunk.bounds.Size.Scale(tiledMap.Scale),
+			}
+			distance := rectDistance(focus, bounds)
+			key := tiled.ChunkKey{Layer: li, Col: chunk.col, Row: chunk.row}
+			ids, spawned := state.Chunks[key]
+			if !spawned && distance <= stream.Radius {
+				ids = make([]goecs.EntityID, 0)
+				if chunked {
+					ids = append(ids, tm.addChunk(world, ent.ID(), mapDef, li, depth-float32(li), chunk))
+				} else {
+					for _, cell := range chunk.cells {
+						tile := layerTile(mapDef, layer, cell.col, cell.row)
+						if tile.IsNil() {
+							continue
+						}
+						id := tm.addTile(world, mapDef, tiledMap, base, layer, depth-float32(li), cell, tile)
+						tm.track(ent.ID(), id)
+						ids = append(ids, id)
+					}
+				}
+				state.Chunks[key] = ids
+			} else if spawned && distance > stream.Radius*streamKeepFactor {
+				for _, id := range ids {
+					tm.untrack(ent.ID(), id)
+					if chunkEnt := world.Get(id); chunkEnt != nil && chunkEnt.ID() == id {
+						if err = world.Remove(id); err != nil {
+							return
+						}
+					}
+				}
+				delete(state.Chunks, key)
+			}
+		}
+	}
+	return
+}
+
+// streamedCell returns the tiled.ChunkKey for a cell of a tile layer in a tiled.Stream map, and if that chunk is
+// spawned
+func streamedCell(tiledMap tiled.Map, state tiled.MapState, layer, col, row int) (key tiled.ChunkKey,
+	spawned bool) {
+	size := streamChunkSize(tiledMap)
+	key = tiled.ChunkKey{Layer: layer, Col: (col / size) * size, Row: (row / size) * size}
+	_, spawned = state.Chunks[key]
+	return
+}
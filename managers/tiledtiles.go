@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"fmt"
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/sprite"
+	"github.com/juan-medina/gosge/components/tiled"
+	gotiled "github.com/lafriks/go-tiled"
+)
+
+// findLayer returns the index and the tile layer with a given name
+func findLayer(mapDef components.TiledMapDef, name string) (int, components.TiledLayerDef, error) {
+	for i, layer := range mapDef.Layers {
+		if layer.Kind == components.TiledTileLayer && layer.Name == name {
+			return i, layer, nil
+		}
+	}
+	return 0, components.TiledLayerDef{}, fmt.Errorf("can not find layer %q in tiled map", name)
+}
+
+// cellIndex returns the index of a cell in the layer tiles
+func cellIndex(mapDef components.TiledMapDef, col, row int) (int, error) {
+	if col < 0 || row < 0 || col >= mapDef.Data.Width || row >= mapDef.Data.Height {
+		return 0, fmt.Errorf("tile %d,%d is outside the tiled map", col, row)
+	}
+	return (row * mapDef.Data.Width) + col, nil
+}
+
+// blockInfo returns the tiled.BlockInfo for a tile in a layer
+func blockInfo(mapDef components.TiledMapDef, tiledMap tiled.Map, l *gotiled.Layer, col, row int,
+	tile *gotiled.LayerTile) tiled.BlockInfo {
+	info := tiled.BlockInfo{
+		Map:   tiledMap.Name,
+		Layer: l.Name,
+		Row:   row,
+		Col:   col,
+	}
+	if !tile.IsNil() {
+		info.GID = tile.Tileset.FirstGID + tile.ID
+		info.Properties = mapDef.Properties[info.GID]
+	}
+	return info
+}
+
+// GetTile returns the tiled.BlockInfo for a tile in a layer of a map entity
+func (tm tiledManager) GetTile(world *goecs.World, mapID goecs.EntityID, layer string, col, row int) (
+	info tiled.BlockInfo, err error) {
+	var tiledMap tiled.Map
+	var mapDef components.TiledMapDef
+	if tiledMap, _, mapDef, err = tm.getMap(world, mapID); err != nil {
+		return
+	}
+	var def components.TiledLayerDef
+	if _, def, err = findLayer(mapDef, layer); err != nil {
+		return
+	}
+	if _, err = cellIndex(mapDef, col, row); err != nil {
+		return
+	}
+	info = blockInfo(mapDef, tiledMap, def.Tiles, col, row, layerTile(mapDef, def, col, row))
+	return
+}
+
+// FindTiles returns the tiled.BlockInfo for the tiles in a layer of a map entity that have a property with a value,
+// if the layer is empty it will search in all the layers
+func (tm tiledManager) FindTiles(world *goecs.World, mapID goecs.EntityID, layer string, property string,
+	value string) (result []tiled.BlockInfo, err error) {
+	var tiledMap tiled.Map
+	var mapDef components.TiledMapDef
+	if tiledMap, _, mapDef, err = tm.getMap(world, mapID); err != nil {
+		return
+	}
+	if layer != "" {
+		if _, _, err = findLayer(mapDef, layer); err != nil {
+			return
+		}
+	}
+
+	result = make([]tiled.BlockInfo, 0)
+	for _, def := range mapDef.Layers {
+		if def.Kind != components.TiledTileLayer || (layer != "" && def.Name != layer) {
+			continue
+		}
+		layerTiles(mapDef, def, func(col, row int, tile *gotiled.LayerTile) {
+			if v, ok := mapDef.Properties[tile.Tileset.FirstGID+tile.ID][property]; ok && v == value {
+				result = append(result, blockInfo(mapDef, tiledMap, def.Tiles, col, row, tile))
+			}
+		})
+	}
+	return
+}
+
+// SetTile changes the tile in a layer of a map entity to a global block id, that may include flip flags, or 0 to
+// remove it, the tile entities spawned for this map entity will be updated, the change is done in the map data so
+// other entities using the same map will get it when they spawn their tiles or draw them in chunks
+func (tm tiledManager) SetTile(world *goecs.World, mapID goecs.EntityID, layer string, col, row int,
+	gid uint32) (err error) {
+	var tiledMap tiled.Map
+	var mapDef components.TiledMapDef
+	if tiledMap, _, mapDef, err = tm.getMap(world, mapID); err != nil {
+		return
+	}
+	var li int
+	var def components.TiledLayerDef
+	if li, def, err = findLayer(mapDef, layer); err != nil {
+		return
+	}
+	if _, err = cellIndex(mapDef, col, row); err != nil {
+		return
+	}
+	var tile *gotiled.LayerTile
+	if tile, err = mapDef.Data.TileGIDToTile(gid); err != nil {
+		return
+	}
+	setLayerTile(mapDef, def, col, row, tile)
+
+	// if the map has not spawn its tiles yet, or the layer is draw in chunks from the map data, there is nothing
+	// else to update
+	ent := world.Get(mapID)
+	if ent.NotContains(tiled.TYPE.MapState) || tm.chunkedLayer(tiledMap, def) {
+		return
+	}
+	// the map sprites are at the map state position, that may not be the map position until the next update
+	state := tiled.Get.MapState(ent)
+	base := layerPosition(def, tiledMap.Scale, state.Origin, state.Position)
+
+	// update the entities for this tile, only the ones of this map entity
+	info := blockInfo(mapDef, tiledMap, def.Tiles, col, row, tile)
+	found := false
+	toRemove := make([]goecs.EntityID, 0)
+	for id := range tm.entities[mapID] {
+		tileEnt := world.Get(id)
+		if tileEnt == nil || tileEnt.ID() != id {
+			tm.untrack(mapID, id)
+			continue
+		}
+		if tileEnt.NotContains(tiled.TYPE.BlockInfo) || tileEnt.NotContains(sprite.TYPE) ||
+			tileEnt.NotContains(geometry.TYPE.Point) {
+			continue
+		}
+		current := tiled.Get.BlockInfo(tileEnt)
+		if current.Layer != layer || current.Col != col || current.Row != row {
+			continue
+		}
+		found = true
+		if tile.IsNil() {
+			toRemove = append(toRemove, tileEnt.ID())
+			continue
+		}
+		tm.updateTile(world, tileEnt, mapDef, tiledMap, base, tile, info)
+	}
+	for _, id := range toRemove {
+		tm.untrack(mapID, id)
+		if err = world.Remove(id); err != nil {
+			return
+		}
+	}
+
+	// if we did not have an entity for this tile add it
+	if !found && !tile.IsNil() && def.Visible {
+		// on streamed maps only if its chunk is spawned
+		streamed := ent.Contains(tiled.TYPE.Stream)
+		key, spawned := streamedCell(tiledMap, state, li, col, row)
+		if streamed && !spawned {
+			return
+		}
+		depth := DefaultLayer
+		if ent.Contains(effects.TYPE.Layer) {
+			depth = effects.Get.Layer(ent).Depth
+		}
+		id := tm.addTile(world, mapDef, tiledMap, base, def, depth-float32(li), tileCell{col: col, row: row}, tile)
+		tm.track(mapID, id)
+		if streamed {
+			state.Chunks[key] = append(state.Chunks[key], id)
+		}
+	}
+	return
+}
+
+// updateTile updates an existing tile entity to a new tile
+func (tm tiledManager) updateTile(world *goecs.World, ent *goecs.Entity, mapDef components.TiledMapDef,
+	tiledMap tiled.Map, base geometry.Point, tile *gotiled.LayerTile, info tiled.BlockInfo) {
+	spr := sprite.Get(ent)
+	spr.Name = tileSpriteName(info.GID)
+	spr.FlipX = tile.HorizontalFlip
+	spr.FlipY = tile.VerticalFlip
+	ent.Set(spr)
+	ent.Set(info)
+
+	// the new tile may have a different size or offset
+	pos, bottom := tm.tileDrawPosition(mapDef, tiledMap, base, info.Col, info.Row, spr.Name, tile.Tileset)
+	ent.Set(pos)
+	if ent.Contains(effects.TYPE.YSort) {
+		ent.Set(effects.YSort{Offset: bottom - pos.Y})
+	}
+
+	if ent.Contains(tiled.TYPE.AnimatedTile) {
+		ent.Remove(tiled.TYPE.AnimatedTile)
+	}
+	tm.addAnimatedTile(world, mapDef, tiledMap, ent.ID(), info.GID)
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright (c) 2020 Juan Medina.
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package managers
+
+import (
+	"github.com/juan-medina/goecs"
+	"github.com/juan-medina/gosge/components/color"
+	"github.com/juan-medina/gosge/components/effects"
+	"github.com/juan-medina/gosge/components/geometry"
+	"github.com/juan-medina/gosge/components/shapes"
+	"github.com/juan-medina/gosge/components/sprite"
+	"github.com/juan-medina/gosge/components/ui"
+	"github.com/juan-medina/gosge/events"
+)
+
+type tweenManager struct{}
+
+// finishedTween is a tween that has finish in this frame
+type finishedTween struct {
+	ent    *goecs.Entity
+	target goecs.EntityID
+	tween  effects.Tween
+}
+
+func (tm tweenManager) System(world *goecs.World, delta float32) error {
+	finished := make([]finishedTween, 0)
+	orphans := make([]*goecs.Entity, 0)
+
+	for it := world.Iterator(effects.TYPE.Tween); it != nil; it = it.Next() {
+		ent := it.Value()
+		tween := effects.Get.Tween(ent)
+
+		// get the entity to animate
+		target := ent
+		if tween.Target != 0 {
+			if target = world.Get(tween.Target); target == nil || target.ID() != tween.Target {
+				orphans = append(orphans, ent)
+				continue
+			}
+		}
+
+		// init the state or get it from entity
+		var state effects.TweenState
+		if ent.Contains(effects.TYPE.TweenState) {
+			state = effects.Get.TweenState(ent)
+		}
+
+		// wait for the delay
+		if !state.Started {
+			if state.Waited < tween.Delay {
+				state.Waited += delta
+				ent.Set(state)
+				continue
+			}
+			if tween.FromCurrent {
+				tween = tm.fromCurrent(target, tween)
+				ent.Set(tween)
+			}
+			state.Started = true
+		} else {
+			state.Elapsed += delta
+		}
+
+		// advance the plays that we have completed
+		done := tween.Time <= 0
+		for !done && state.Elapsed >= tween.Time {
+			if tween.Repeat >= 0 && state.Played >= tween.Repeat {
+				done = true
+				break
+			}
+			state.Elapsed -= tween.Time
+			state.Played++
+			if tween.Yoyo {
+				state.Backward = !state.Backward
+			}
+		}
+
+		var t float32 = 1
+		if !done {
+			t = state.Elapsed / tween.Time
+		}
+		if state.Backward {
+			t = 1 - t
+		}
+		tm.apply(target, tween, tween.Ease.Apply(t))
+
+		if done {
+			finished = append(finished, finishedTween{ent: ent, target: target.ID(), tween: tween})
+		} else {
+			ent.Set(state)
+		}
+	}
+
+	for _, ft := range finished {
+		tm.finish(world, ft)
+	}
+
+	// tweens which target is gone are removed, and their entities if they are left empty
+	for _, ent := range orphans {
+		ent.Remove(effects.TYPE.TweenState)
+		ent.Remove(effects.TYPE.Tween)
+		if ent.IsEmpty() {
+			if err := world.Remove(ent.ID()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// finish notify that a tween has finished and replace it with the next in the chain, if there is any, otherwise
+// remove it, and its entity if it is left empty
+func (tm tweenManager) finish(world *goecs.World, ft finishedTween) {
+	world.Signal(events.TweenFinishedEvent{Entity: ft.ent.ID(), Target: ft.target})
+	if ft.tween.Event != nil {
+		world.Signal(ft.tween.Event)
+	}
+
+	ft.ent.Remove(effects.TYPE.TweenState)
+	if len(ft.tween.Then) > 0 {
+		next := ft.tween.Then[0]
+		next.Then = append(append([]effects.Tween{}, next.Then...), ft.tween.Then[1:]...)
+		if next.Target == 0 {
+			next.Target = ft.tween.Target
+		}
+		ft.ent.Set(next)
+		return
+	}
+
+	ft.ent.Remove(effects.TYPE.Tween)
+	if ft.ent.IsEmpty() {
+		_ = world.Remove(ft.ent.ID())
+	}
+}
+
+// fromCurrent returns an effects.Tween that starts from the current value of its property
+func (tm tweenManager) fromCurrent(target *goecs.Entity, tween effects.Tween) effects.Tween {
+	switch tween.Property {
+	case effects.TweenPosition:
+		if target.Contains(geometry.TYPE.Point) {
+			tween.FromPoint = geometry.Get.Point(target)
+		}
+	case effects.TweenScale:
+		if target.Contains(sprite.TYPE) {
+			tween.From = sprite.Get(target).Scale
+		} else if target.Contains(ui.TYPE.SpriteButton) {
+			tween.From = ui.Get.SpriteButton(target).Scale
+		} else if target.Contains(shapes.TYPE.Box) {
+			tween.From = shapes.Get.Box(target).Scale
+		} else if target.Contains(shapes.TYPE.SolidBox) {
+			tween.From = shapes.Get.SolidBox(target).Scale
+		}
+	case effects.TweenRotation:
+		if target.Contains(sprite.TYPE) {
+			tween.From = sprite.Get(target).Rotation
+		}
+	case effects.TweenAlpha:
+		if target.Contains(color.TYPE.Solid) {
+			tween.From = float32(color.Get.Solid(target).A)
+		}
+	case effects.TweenTint:
+		if target.Contains(color.TYPE.Solid) {
+			tween.FromColor = color.Get.Solid(target)
+		}
+	case effects.TweenProgress:
+		if target.Contains(ui.TYPE.ProgressBar) {
+			tween.From = ui.Get.ProgressBar(target).Current
+		}
+	case effects.TweenCustom:
+		if tween.Getter != nil {
+			tween.From = tween.Getter(target)
+		}
+	}
+	tween.FromCurrent = false
+	return tween
+}
+
+// apply sets the property of an effects.Tween in a target entity for a given eased progress
+func (tm tweenManager) apply(target *goecs.Entity, tween effects.Tween, progress float32) {
+	value := tween.From + ((tween.To - tween.From) * progress)
+	switch tween.Property {
+	case effects.TweenPosition:
+		target.Set(geometry.Point{
+			X: tween.FromPoint.X + ((tween.ToPoint.X - tween.FromPoint.X) * progress),
+			Y: tween.FromPoint.Y + ((tween.ToPoint.Y - tween.FromPoint.Y) * progress),
+		})
+	case effects.TweenScale:
+		if target.Contains(sprite.TYPE) {
+			spr := sprite.Get(target)
+			spr.Scale = value
+			target.Set(spr)
+		} else if target.Contains(ui.TYPE.SpriteButton) {
+			sbn := ui.Get.SpriteButton(target)
+			sbn.Scale = value
+			target.Set(sbn)
+		} else if target.Contains(shapes.TYPE.Box) {
+			box := shapes.Get.Box(target)
+			box.Scale = value
+			target.Set(box)
+		} else if target.Contains(shapes.TYPE.SolidBox) {
+			box := shapes.Get.SolidBox(target)
+			box.Scale = value
+			target.Set(box)
+		}
+	case effects.TweenRotation:
+		if target.Contains(sprite.TYPE) {
+			spr := sprite.Get(target)
+			spr.Rotation = value
+			target.Set(spr)
+		}
+	case effects.TweenAlpha:
+		clr := color.White
+		if target.Contains(color.TYPE.Solid) {
+			clr = color.Get.Solid(target)
+		}
+		target.Set(clr.Alpha(clampChannel(value)))
+	case effects.TweenTint:
+		from, to := tween.FromColor, tween.ToColor
+		target.Set(color.Solid{
+			R: clampChannel(float32(from.R) + ((float32(to.R) - float32(from.R)) * progress)),
+			G: clampChannel(float32(from.G) + ((float32(to.G) - float32(from.G)) * progress)),
+			B: clampChannel(float32(from.B) + ((float32(to.B) - float32(from.B)) * progress)),
+			A: clampChannel(float32(from.A) + ((float32(to.A) - float32(from.A)) * progress)),
+		})
+	case effects.TweenProgress:
+		if target.Contains(ui.TYPE.ProgressBar) {
+			bar := ui.Get.ProgressBar(target)
+			bar.Current = value
+			target.Set(bar)
+		}
+	case effects.TweenCustom:
+		if tween.Setter != nil {
+			tween.Setter(target, value)
+		}
+	}
+}
+
+// clampChannel returns a color channel from a value, that easing curves may take out of range
+func clampChannel(value float32) uint8 {
+	if value < 0 {
+		return 0
+	}
+	if value > 255 {
+		return 255
+	}
+	return uint8(value)
+}
+
+// Tweens is a manager.WithSystem that handle effects.Tween
+func Tweens() WithSystem {
+	return &tweenManager{}
+}
@@ -69,6 +69,7 @@ func (uim *uiManager) System(world *goecs.World, delta float32) error {
 	uim.flatButtons(world)
 	uim.progressBars(world)
 	uim.spriteButtons(world)
+	uim.textStyles(world, delta)
 	uim.handleKeys(world, delta)
 	return nil
 }
@@ -566,6 +567,24 @@ func (uim *uiManager) spriteButtonsMouseUp(world *goecs.World, _ events.MouseUpE
 	}
 }
 
+func (uim *uiManager) textStyles(world *goecs.World, delta float32) {
+	for it := world.Iterator(ui.TYPE.TextStyle); it != nil; it = it.Next() {
+		ent := it.Value()
+		style := ui.Get.TextStyle(ent)
+		if style.Animation == ui.NoTextAnimation {
+			continue
+		}
+
+		var state ui.TextStyleState
+		if ent.Contains(ui.TYPE.TextStyleState) {
+			state = ui.Get.TextStyleState(ent)
+		}
+
+		state.Time += delta
+		ent.Set(state)
+	}
+}
+
 func (uim *uiManager) focusControl(world *goecs.World, control *goecs.Entity) {
 	for it := world.Iterator(ui.TYPE.ControlState); it != nil; it = it.Next() {
 		ent := it.Value()